@@ -0,0 +1,71 @@
+package gui
+
+// 父容器(内容区域)的一条边
+type Edge uint8
+
+const (
+	EdgeLeft Edge = iota
+	EdgeRight
+	EdgeTop
+	EdgeBottom
+)
+
+// 元素和父容器某条边的距离 = 父容器的大小 * fraction + offset
+type anchor struct {
+	edge     Edge
+	fraction float32
+	offset   float32
+	valid    bool
+}
+
+// 把元素的同一侧的边放在距离父容器 edge 边 fraction(相对于父容器的大小)+offset 的位置,
+// 比如 Anchor(EdgeRight, .3, 0) 表示元素的右边在距离父容器右边 30% 的地方.
+// 左右和上下各自独立, 设置后覆盖这个方向的 Gravity
+func (c *cursor) Anchor(edge Edge, fraction, offset float32) *cursor {
+	c.Flag |= FlagAnchor
+	a := anchor{edge, finite(fraction), finite(offset), true}
+	if edge == EdgeLeft || edge == EdgeRight {
+		c.anchorH = a
+	} else {
+		c.anchorV = a
+	}
+	return c
+}
+
+func (c *cursor) AnchorLeft(fraction float32) *cursor {
+	return c.Anchor(EdgeLeft, fraction, 0)
+}
+
+func (c *cursor) AnchorRight(fraction float32) *cursor {
+	return c.Anchor(EdgeRight, fraction, 0)
+}
+
+func (c *cursor) AnchorTop(fraction float32) *cursor {
+	return c.Anchor(EdgeTop, fraction, 0)
+}
+
+func (c *cursor) AnchorBottom(fraction float32) *cursor {
+	return c.Anchor(EdgeBottom, fraction, 0)
+}
+
+// 按锚点计算元素的位置(相对于 Group)
+func (g *Group) applyAnchor(elem *Element) {
+	var (
+		cw, ch = g.Content()
+		dx, dy = g.Extent(elem)
+	)
+	if a := elem.anchorH; a.valid {
+		d := cw*a.fraction + a.offset
+		if a.edge == EdgeRight {
+			d = cw - d - dx
+		}
+		elem.X = g.Padding.Left + d + elem.Left
+	}
+	if a := elem.anchorV; a.valid {
+		d := ch*a.fraction + a.offset
+		if a.edge == EdgeBottom {
+			d = ch - d - dy
+		}
+		elem.Y = g.Padding.Top + d + elem.Top
+	}
+}
@@ -0,0 +1,70 @@
+package gui
+
+import "korok.io/korok/engi/math"
+
+type aspectFill struct {
+	// 宽/高, 0 表示不限制比例
+	ratio float32
+	// 宽度填满父容器的内容区域
+	fill bool
+	// 最大高度, 0 表示不限制
+	maxH float32
+	// 高度被限制之后按比例缩小宽度
+	keep bool
+	// 固定的高度, 宽度按比例计算, 0 表示不固定
+	height float32
+}
+
+// 设置元素的宽高比(宽/高), 高度由宽度计算, 0 取消
+func (lyt *LayoutManager) SetAspectRatio(id ID, ratio float32) {
+	lyt.obtain(id).aspect.ratio = math.Max(finite(ratio), 0)
+}
+
+// 元素的宽度填满父容器的内容区域(去掉元素自己的 margin)
+func (lyt *LayoutManager) SetFillParent(id ID, fill bool) {
+	lyt.obtain(id).aspect.fill = fill
+}
+
+// 限制元素的最大高度, 0 取消
+// keepAspect 为 true 时, 高度被限制后按宽高比重新计算宽度, 否则只限制高度
+func (lyt *LayoutManager) SetMaxHeight(id ID, h float32, keepAspect bool) {
+	a := &lyt.obtain(id).aspect
+	a.maxH, a.keep = math.Max(finite(h), 0), keepAspect
+}
+
+// 固定元素的高度, 宽度按 SetAspectRatio 计算, 比如 FlowLayout 里高度统一的图片, 0 取消
+// 设置后 SetFillParent 不再起作用
+func (lyt *LayoutManager) SetAspectHeight(id ID, h float32) {
+	lyt.obtain(id).aspect.height = math.Max(finite(h), 0)
+}
+
+// 固定高度时: 高度 -> 按比例计算宽度 -> 限制高度
+// 否则依次计算: 填满宽度 -> 按比例计算高度 -> 限制高度 -> 按比例重新计算宽度
+// 返回是否修改了元素的大小
+func (lyt *LayoutManager) applyAspect(elem *Element) bool {
+	a := &elem.aspect
+	if !a.fill && a.ratio == 0 && a.maxH == 0 && a.height == 0 {
+		return false
+	}
+	if a.height > 0 {
+		elem.H = a.height
+		if a.ratio > 0 {
+			elem.W = elem.H * a.ratio
+		}
+	} else {
+		if a.fill {
+			cw, _ := lyt.hGroup.Content()
+			elem.W = math.Max(cw-elem.Left-elem.Right, 0)
+		}
+		if a.ratio > 0 {
+			elem.H = elem.W / a.ratio
+		}
+	}
+	if a.maxH > 0 && elem.H > a.maxH {
+		elem.H = a.maxH
+		if a.keep && a.ratio > 0 {
+			elem.W = elem.H * a.ratio
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package gui
+
+import "testing"
+
+func TestAspectFill(t *testing.T) {
+	for _, keep := range []bool{false, true} {
+		lyt := New()
+		lyt.spacing = 0
+		lyt.SetAspectRatio(2, 2)
+		lyt.SetFillParent(2, true)
+		lyt.SetMaxHeight(2, 100, keep)
+
+		frame := func(w float32) {
+			lyt.Move(0, 0)
+			lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+			lyt.SetSize(w, 500)
+			elem, _ := lyt.BeginElement(2)
+			lyt.EndElement(elem)
+			lyt.EndLayout()
+		}
+
+		// 没有超过最大高度
+		frame(100)
+		frame(100)
+		if e, _ := lyt.Element(2); e.W != 100 || e.H != 50 {
+			t.Error("height should follow the aspect:", e.Bound)
+		}
+
+		// 高度被限制
+		frame(400)
+		e, _ := lyt.Element(2)
+		if e.H != 100 {
+			t.Error("height should be clamped:", e.Bound)
+		}
+		if keep && e.W != 200 {
+			t.Error("width should be re-derived from the aspect:", e.Bound)
+		}
+		if !keep && e.W != 400 {
+			t.Error("width should still fill the parent:", e.Bound)
+		}
+	}
+}
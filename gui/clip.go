@@ -0,0 +1,106 @@
+package gui
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"korok.io/korok/engi/math"
+)
+
+// 设置裁剪区域(绝对坐标), 之后声明的元素只在这个区域内可见
+// 嵌套的裁剪区域取交集, 交集为空时里面的元素全部被裁剪掉
+func (lyt *LayoutManager) PushClip(b Bound) {
+	lyt.PushClipRadius(b, 0)
+}
+
+// 圆角的裁剪区域, 比如 SetCornerRadius 的 Group 裁剪子元素, 里面的元素都会报告这个圆角
+// 嵌套的直角裁剪区域没有把上一层缩小时沿用上一层的圆角
+func (lyt *LayoutManager) PushClipRadius(b Bound, r float32) {
+	r = math.Max(finite(r), 0)
+	c := clipRect{Bound: b, local: b}
+	if len(lyt.groupStack) > 0 {
+		c.owner = lyt.hGroup.id
+	}
+	if n := len(lyt.clips); n > 0 {
+		top := lyt.clips[n-1]
+		if c.Bound = top.Intersect(b); c.Bound == top.Bound && r == 0 {
+			r = top.radius
+		}
+		if top.owner == c.owner {
+			c.local = top.local.Intersect(b)
+		}
+	}
+	c.radius = r
+	lyt.clips = append(lyt.clips, c)
+}
+
+// 恢复上一层的裁剪区域
+func (lyt *LayoutManager) PopClip() {
+	if n := len(lyt.clips); n > 0 {
+		lyt.clips = lyt.clips[:n-1]
+	}
+}
+
+type clipRect struct {
+	Bound
+	radius float32
+	// 声明时所在的 Group 和在这个 Group 中 push 的区域的交集, 滚动时用来重新计算, 见 shiftClips
+	owner ID
+	local Bound
+}
+
+func (lyt *LayoutManager) currentClip() (clip clipRect, ok bool) {
+	if n := len(lyt.clips); n > 0 {
+		clip, ok = lyt.clips[n-1], true
+	}
+	return
+}
+
+func (elem *Element) setClip(c clipRect, ok bool) {
+	elem.clip, elem.clipRadius, elem.clipped = c.Bound, c.radius, ok
+	elem.clipOwner, elem.clipLocal = c.owner, c.local
+}
+
+// 和 Visit 一样, 但是跳过完全被裁剪掉的元素
+func (lyt *LayoutManager) VisitVisible(fn func(info ElementInfo), opts ...VisitOption) {
+	lyt.Visit(func(info ElementInfo) {
+		if elem, ok := lyt.Element(info.ID); ok && elem.clipped {
+			if elem.clip.Intersect(info.Bound).Empty() {
+				return
+			}
+		}
+		fn(info)
+	}, opts...)
+}
+
+// Group id 滚动了 d 之后, 在它的子 Group 中设置的裁剪区域跟着子 Group 一起移动,
+// 然后和外层(不移动的)裁剪区域重新求交集. 父元素在 uiElements 中总是排在子元素前面
+func (lyt *LayoutManager) shiftClips(id ID, d mgl32.Vec2) {
+	if d[0] == 0 && d[1] == 0 {
+		return
+	}
+	for i := range lyt.uiElements {
+		e := &lyt.uiElements[i]
+		if !e.clipped || e.clipOwner == id || !lyt.descendant(e.clipOwner, id) {
+			continue
+		}
+		e.clipLocal.X, e.clipLocal.Y = e.clipLocal.X+d[0], e.clipLocal.Y+d[1]
+		e.clip = e.clipLocal
+		if owner, ok := lyt.Element(e.clipOwner); ok && owner.clipped && owner != e {
+			e.clip = owner.clip.Intersect(e.clipLocal)
+		}
+	}
+}
+
+// id 是否在 Group ancestor 里面(不包括 ancestor 自己)
+func (lyt *LayoutManager) descendant(id, ancestor ID) bool {
+	elem, ok := lyt.Element(id)
+	for depth := 0; ok && depth < len(lyt.uiElements); depth++ {
+		if elem.parent == elem.id {
+			return false
+		}
+		if elem.parent == ancestor {
+			return true
+		}
+		elem, ok = lyt.Element(elem.parent)
+	}
+	return false
+}
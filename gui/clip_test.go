@@ -0,0 +1,152 @@
+package gui
+
+import (
+	"testing"
+)
+
+func TestNestedClip(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearOverLay, layoutOf(lyt, 1))
+		lyt.PushClip(Bound{0, 0, 100, 100})
+		lyt.PushClip(Bound{50, 50, 100, 100})
+
+		// only in the outer clip
+		lyt.Move(10, 10)
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+
+		// in both clips
+		lyt.Move(60, 60)
+		elem, _ = lyt.BeginElement(3)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+
+		lyt.PopClip()
+		if clip, _ := lyt.currentClip(); clip.Bound != (Bound{0, 0, 100, 100}) {
+			t.Error("pop should restore the previous clip:", clip)
+		}
+
+		// back in the outer clip
+		lyt.Move(10, 10)
+		elem, _ = lyt.BeginElement(4)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+		lyt.PopClip()
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	visible := map[ID]bool{}
+	lyt.VisitVisible(func(info ElementInfo) {
+		visible[info.ID] = true
+	})
+	if visible[2] {
+		t.Error("element outside the intersection should be culled")
+	}
+	if !visible[3] || !visible[4] {
+		t.Error("visible elements:", visible)
+	}
+}
+
+func TestSelfClip(t *testing.T) {
+	lyt := New()
+
+	p := NewProperty()
+	p.Clip = true
+	p.Width, p.Height = 40, 30
+
+	frame := func() {
+		lyt.Move(10, 10)
+		elem, _ := lyt.BeginElement(2)
+		lyt.EndElement(elem)
+		lyt.Cursor.SetSize(40, 30).To(3)
+		elem, _ = lyt.BeginElement(3)
+		lyt.EndElement(elem)
+	}
+	frame()
+	lyt.SetProperty(2, p)
+	frame()
+
+	lyt.Visit(func(info ElementInfo) {
+		switch info.ID {
+		case 2:
+			if !info.Clipped || info.Clip != info.Bound {
+				t.Error("self clip should equal the element bound:", info.Clip, info.Bound)
+			}
+		case 3:
+			if info.Clipped {
+				t.Error("element without clip:", info.Clip)
+			}
+		}
+	})
+}
+
+func TestCornerRadius(t *testing.T) {
+	lyt := New()
+
+	clip := NewProperty()
+	clip.Clip = true
+	lyt.SetProperty(1, clip)
+	lyt.SetProperty(2, clip)
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetCornerRadius(8)
+		// Group 圆角裁剪它的子元素
+		lyt.PushClipRadius(Bound{0, 0, 100, 100}, 8)
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+		// 没有缩小的直角裁剪沿用上一层的圆角
+		lyt.PushClip(Bound{-10, -10, 200, 200})
+		elem, _ = lyt.BeginElement(3)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+		lyt.PopClip()
+		lyt.PushClip(Bound{0, 0, 50, 50})
+		elem, _ = lyt.BeginElement(4)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+		lyt.PopClip()
+		lyt.PopClip()
+		elem, _ = lyt.BeginElement(5)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	radius := map[ID]float32{}
+	lyt.Visit(func(info ElementInfo) {
+		if info.Clipped {
+			radius[info.ID] = info.CornerRadius
+		}
+	})
+	if r, ok := radius[1]; !ok || r != 8 {
+		t.Error("group clip should report its radius:", r, ok)
+	}
+	// SetProperty 不会覆盖 SetCornerRadius
+	lyt.SetProperty(1, clip)
+	if elem, _ := lyt.Element(1); elem.radius != 8 {
+		t.Error("SetProperty should not reset the corner radius:", elem.radius)
+	}
+	if r, ok := radius[2]; !ok || r != 8 {
+		t.Error("children of a rounded clip should report its radius:", r, ok)
+	}
+	if r, ok := radius[3]; !ok || r != 8 {
+		t.Error("a clip that does not shrink should keep the radius:", r, ok)
+	}
+	if r, ok := radius[4]; !ok || r != 0 {
+		t.Error("a smaller square clip should not be rounded:", r, ok)
+	}
+	if _, ok := radius[5]; ok {
+		t.Error("element outside the clip should not be clipped")
+	}
+}
@@ -0,0 +1,108 @@
+package gui
+
+import (
+	"fmt"
+	"log"
+	geo "math"
+
+	"korok.io/korok/engi/math"
+)
+
+// 开发时打开, 布局的不变量被破坏时 panic:
+// EndFrame 时 Group 没有配对结束, 元素的大小是负数, 坐标是 NaN, 同一个 Group 里 ID 重复
+// 默认关闭, 这时不做检查或者静默修正(负数大小按 0, NaN 坐标按 0)
+func (lyt *LayoutManager) SetDebug(debug bool) {
+	lyt.debug = debug
+}
+
+func (lyt *LayoutManager) Debug() bool {
+	return lyt.debug
+}
+
+// 返回 ok, 调试模式下 ok 为 false 时 panic
+func (lyt *LayoutManager) assert(ok bool, format string, args ...interface{}) bool {
+	if !ok && lyt.debug {
+		panic(fmt.Sprintf("gui: "+format, args...))
+	}
+	return ok
+}
+
+func isNaN(v float32) bool {
+	return geo.IsNaN(float64(v))
+}
+
+// EndElement 时检查元素
+func (lyt *LayoutManager) checkElement(elem *Element) {
+	if !lyt.assert(elem.W >= 0 && elem.H >= 0, "element %d has invalid size %vx%v", elem.id, elem.W, elem.H) {
+		elem.W, elem.H = math.Max(finite(elem.W), 0), math.Max(finite(elem.H), 0)
+	}
+	if !lyt.assert(!isNaN(elem.X) && !isNaN(elem.Y), "element %d has NaN position", elem.id) {
+		elem.X, elem.Y = finite(elem.X), finite(elem.Y)
+	}
+}
+
+// 元素是否已经在当前 Group 中声明过
+func (lyt *LayoutManager) declared(elem *Element) bool {
+	serial := lyt.hGroup.serial
+	return serial != 0 && elem.declaredIn == serial
+}
+
+// 不使用 BeginFrame 时根布局一直是同一个, 根布局的第一个子元素再次声明时当作新的一帧,
+// 给根布局换一个新的编号. 所以根布局的第一个子元素重复声明时检查不出来
+func (lyt *LayoutManager) rootPass(id ID) {
+	if lyt.framed || len(lyt.groupStack) != 1 {
+		return
+	}
+	switch root := lyt.hGroup; {
+	case !root.hasFirst:
+		root.first, root.hasFirst = id, true
+	case root.first == id:
+		lyt.groupSerial++
+		root.serial = lyt.groupSerial
+	}
+}
+
+// 同一个 Group 中重复声明的 ID: 保留第一个, 第二个被丢弃(返回一个临时的元素, 对它的修改没有效果),
+// 只打印一次日志
+func (lyt *LayoutManager) duplicate(elem *Element) *Element {
+	lyt.assert(false, "duplicate id %d in group %d", elem.id, lyt.hGroup.id)
+	if !lyt.dupLogged {
+		log.Println("gui: duplicate id", elem.id, "in group", lyt.hGroup.id, ", the second one is dropped")
+		lyt.dupLogged = true
+	}
+	lyt.discard = Element{id: elem.id}
+	return &lyt.discard
+}
+
+// 调试层上绘制的一个矩形
+// DebugElement/DebugGroup 是元素的区域, DebugBaseline 是一条高度为 0 的基线(见 SetBaseline)
+type DebugKind uint8
+
+const (
+	DebugElement DebugKind = iota
+	DebugGroup
+	DebugBaseline
+)
+
+type DebugRect struct {
+	ID ID
+	Kind DebugKind
+	Bound
+}
+
+// 调试层需要绘制的所有矩形(绝对坐标), baselines 为 true 时同时返回设置了基线的元素的基线
+func (lyt *LayoutManager) DebugRects(baselines bool) (rects []DebugRect) {
+	for i := 1; i < len(lyt.uiElements); i++ {
+		elem := &lyt.uiElements[i]
+		b := lyt.absBound(elem)
+		kind := DebugElement
+		if elem.group {
+			kind = DebugGroup
+		}
+		rects = append(rects, DebugRect{elem.id, kind, b})
+		if baselines && elem.hasBaseline {
+			rects = append(rects, DebugRect{elem.id, DebugBaseline, Bound{b.X, b.Y + elem.baseline, b.W, 0}})
+		}
+	}
+	return
+}
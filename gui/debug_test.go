@@ -0,0 +1,99 @@
+package gui
+
+import (
+	geo "math"
+	"testing"
+)
+
+// 返回 fn 是否 panic
+func panics(fn func()) (ok bool) {
+	defer func() {
+		ok = recover() != nil
+	}()
+	fn()
+	return
+}
+
+func TestDebugInvariants(t *testing.T) {
+	nan := float32(geo.NaN())
+	cases := map[string]func(lyt *LayoutManager){
+		"unbalanced": func(lyt *LayoutManager) {
+			lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		},
+		"negative size": func(lyt *LayoutManager) {
+			elem, _ := lyt.BeginElement(2)
+			elem.Size(-10, 5)
+			lyt.EndElement(elem)
+		},
+		"nan position": func(lyt *LayoutManager) {
+			elem, _ := lyt.BeginElement(2)
+			elem.X = nan
+			lyt.EndElement(elem)
+		},
+		"duplicate id": func(lyt *LayoutManager) {
+			for i := 0; i < 2; i++ {
+				elem, _ := lyt.BeginElement(2)
+				lyt.EndElement(elem)
+			}
+		},
+	}
+	for name, fn := range cases {
+		for _, debug := range []bool{true, false} {
+			lyt := New()
+			frame := func() {
+				lyt.BeginFrame()
+				fn(lyt)
+				lyt.EndFrame()
+			}
+			// 第一帧只是创建元素
+			frame()
+			lyt.SetDebug(debug)
+			if p := panics(frame); p != debug {
+				t.Errorf("%s: debug=%v, panic=%v", name, debug, p)
+			}
+			if debug {
+				continue
+			}
+			if e, ok := lyt.Element(2); ok && (e.W < 0 || e.H < 0 || isNaN(e.X) || isNaN(e.Y)) {
+				t.Errorf("%s: release should degrade silently: %v", name, e.Bound)
+			}
+		}
+	}
+}
+
+func TestDebugRectsBaseline(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+	lyt.SetBaseline(2, 12)
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		for id := ID(2); id <= 3; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(60, 16)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	baselines := map[ID]Bound{}
+	for _, r := range lyt.DebugRects(true) {
+		if r.Kind == DebugBaseline {
+			baselines[r.ID] = r.Bound
+		}
+	}
+	if b, ok := baselines[2]; !ok || b != (Bound{0, 12, 60, 0}) {
+		t.Error("text element should have a baseline:", b, ok)
+	}
+	if _, ok := baselines[3]; ok {
+		t.Error("box without a baseline should not have one")
+	}
+	for _, r := range lyt.DebugRects(false) {
+		if r.Kind == DebugBaseline {
+			t.Error("baselines should be optional")
+		}
+	}
+}
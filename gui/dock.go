@@ -0,0 +1,57 @@
+package gui
+
+import (
+	"korok.io/korok/engi/math"
+)
+
+// 停靠布局: 在设置了大小的 Group(一般是 BeginDock)中, 依次从剩余区域的某条边切下一条,
+// 最后剩下的区域可以作为中心区域, 再放一个子 Group 嵌套其它布局:
+//
+//	lyt.Dock(EdgeTop, 20)        // 工具栏
+//	... 声明工具栏
+//	b := lyt.DockFill()          // 中心区域
+//	lyt.PushLayout(LinearVertical, center)
+//	lyt.SetSize(b.W, b.H)
+//	...
+//	lyt.EndLayout()
+//
+// 返回的区域相对于 Group 的内容区域(去掉 padding), 光标会移到区域的左上角
+func (lyt *LayoutManager) Dock(edge Edge, size float32) (b Bound) {
+	g := lyt.hGroup
+	r := lyt.dockRemain()
+	size = math.Max(finite(size), 0)
+	switch edge {
+	case EdgeLeft:
+		size = math.Min(size, r.W)
+		b, r.X, r.W = Bound{r.X, r.Y, size, r.H}, r.X+size, r.W-size
+	case EdgeRight:
+		size = math.Min(size, r.W)
+		b, r.W = Bound{r.X + r.W - size, r.Y, size, r.H}, r.W-size
+	case EdgeTop:
+		size = math.Min(size, r.H)
+		b, r.Y, r.H = Bound{r.X, r.Y, r.W, size}, r.Y+size, r.H-size
+	case EdgeBottom:
+		size = math.Min(size, r.H)
+		b, r.H = Bound{r.X, r.Y + r.H - size, r.W, size}, r.H-size
+	}
+	g.dock = r
+	lyt.Move(b.X, b.Y)
+	return
+}
+
+// 剩下的中心区域, 光标移到区域的左上角
+func (lyt *LayoutManager) DockFill() Bound {
+	b := lyt.dockRemain()
+	lyt.Move(b.X, b.Y)
+	return b
+}
+
+// 还没有停靠的区域, 第一次使用时是整个内容区域
+func (lyt *LayoutManager) dockRemain() Bound {
+	g := lyt.hGroup
+	if !g.docked {
+		cw, ch := g.Content()
+		g.dock, g.docked = Bound{0, 0, math.Max(cw, 0), math.Max(ch, 0)}, true
+	}
+	return g.dock
+}
@@ -0,0 +1,44 @@
+package gui
+
+import "testing"
+
+func TestDockFill(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearOverLay, layoutOf(lyt, 1))
+		lyt.SetSize(200, 100).SetPadding(0, 0, 0, 0)
+
+		b := lyt.Dock(EdgeTop, 20)
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(b.W, b.H)
+		lyt.EndElement(elem)
+
+		b = lyt.DockFill()
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 3))
+		lyt.SetSize(b.W, b.H).SetSpacing(0)
+		for id := ID(4); id <= 5; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(50, 10)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	if b, _ := lyt.BoundsOf(2); b != (Bound{0, 0, 200, 20}) {
+		t.Error("top bar:", b)
+	}
+	if b, _ := lyt.BoundsOf(3); b != (Bound{0, 20, 200, 80}) {
+		t.Error("center region:", b)
+	}
+	if b, _ := lyt.BoundsOf(4); b.Y != 20 {
+		t.Error("nested list should start below the top bar:", b)
+	}
+	if b, _ := lyt.BoundsOf(5); b.Y != 30 {
+		t.Error("nested list item:", b)
+	}
+}
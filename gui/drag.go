@@ -0,0 +1,53 @@
+package gui
+
+import "github.com/go-gl/mathgl/mgl32"
+
+type dragState struct {
+	id     ID
+	active bool
+	// 开始拖动时元素的绝对坐标
+	origin mgl32.Vec2
+	offset mgl32.Vec2
+}
+
+// 开始拖动元素, 拖动期间元素的位置只由拖动的偏移决定(见 SetDragOffset),
+// 不参与 Group 的排列, 其它元素按它不存在排列. 元素不存在时返回 false
+// 同时只能拖动一个元素
+func (lyt *LayoutManager) BeginDrag(id ID) bool {
+	elem, ok := lyt.Element(id)
+	if !ok {
+		return false
+	}
+	b := lyt.absBound(elem)
+	lyt.drag = dragState{id: id, active: true, origin: mgl32.Vec2{b.X, b.Y}}
+	return true
+}
+
+// 拖动的偏移, 相对于开始拖动时的位置
+func (lyt *LayoutManager) SetDragOffset(dx, dy float32) {
+	lyt.drag.offset = mgl32.Vec2{finite(dx), finite(dy)}
+}
+
+// 结束拖动, 下一次布局时元素回到正常的排列中
+func (lyt *LayoutManager) EndDrag() {
+	lyt.drag = dragState{}
+}
+
+func (lyt *LayoutManager) Dragging() (id ID, ok bool) {
+	return lyt.drag.id, lyt.drag.active
+}
+
+func (lyt *LayoutManager) isDragged(elem *Element) bool {
+	return lyt.drag.active && lyt.drag.id == elem.id
+}
+
+// 被拖动的元素使用绝对坐标, 见 PlaceAt
+func (lyt *LayoutManager) applyDrag(elem *Element) {
+	if !lyt.isDragged(elem) {
+		return
+	}
+	p := lyt.drag.origin.Add(lyt.drag.offset)
+	elem.X, elem.Y = p[0], p[1]
+	elem.parent = elem.id
+	elem.setClip(clipRect{}, false)
+}
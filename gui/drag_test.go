@@ -0,0 +1,47 @@
+package gui
+
+import "testing"
+
+func TestDrag(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		for id := ID(2); id <= 4; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(50, 20)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	if !lyt.BeginDrag(2) {
+		t.Fatal("drag should start on an existing element")
+	}
+	for _, dy := range []float32{15, 35} {
+		lyt.SetDragOffset(5, dy)
+		frame()
+
+		if b, _ := lyt.BoundsOf(2); b != (Bound{5, dy, 50, 20}) {
+			t.Error("dragged element should follow the drag offset:", b)
+		}
+		b3, _ := lyt.BoundsOf(3)
+		b4, _ := lyt.BoundsOf(4)
+		if b3.Y != 0 || b4.Y != 20 {
+			t.Error("other items should not depend on the dragged element:", b3, b4)
+		}
+	}
+
+	lyt.EndDrag()
+	frame()
+	if b, _ := lyt.BoundsOf(2); b != (Bound{0, 0, 50, 20}) {
+		t.Error("element should return to the flow after the drag:", b)
+	}
+	if b, _ := lyt.BoundsOf(3); b.Y != 20 {
+		t.Error("flow should be restored:", b)
+	}
+}
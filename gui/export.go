@@ -0,0 +1,255 @@
+package gui
+
+import (
+	geo "math"
+)
+
+// 导出的布局结果, Bound 为绝对坐标
+type ElementInfo struct {
+	ID ID
+	Bound
+	Enabled bool
+	// 实际的不透明度: 自己和所有上层 Group 的乘积
+	Opacity float32
+	// 绘制内容时的裁剪区域: PushClip 的区域, 设置了 Property.Clip 时再和元素自己求交集
+	Clip    Bound
+	Clipped bool
+	// 裁剪区域的圆角半径, 0 为直角: Property.Clip 并且 SetCornerRadius 时是它的值, 否则是 PushClipRadius 的值
+	CornerRadius float32
+}
+
+// 导出所有元素的布局结果(不包括默认的根布局)
+func (lyt *LayoutManager) Export() []ElementInfo {
+	if len(lyt.uiElements) < 2 {
+		return nil
+	}
+	result := make([]ElementInfo, 0, len(lyt.uiElements)-1)
+	lyt.Visit(func(info ElementInfo) {
+		result = append(result, info)
+	})
+	return result
+}
+
+// Visit 的选项
+// SkipEmpty: 跳过面积为 0 的 Group 和它里面的元素
+type VisitOption struct {
+	SkipEmpty bool
+}
+
+// 按声明的顺序访问所有元素(不包括默认的根布局), 渲染时使用
+func (lyt *LayoutManager) Visit(fn func(info ElementInfo), opts ...VisitOption) {
+	var opt VisitOption
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	for i := 1; i < len(lyt.uiElements); i++ {
+		elem := &lyt.uiElements[i]
+		if opt.SkipEmpty && lyt.inEmptyGroup(elem) {
+			continue
+		}
+		fn(lyt.info(elem))
+	}
+}
+
+// VisitTree 的事件类型
+// VisitGroupBegin: 进入 Group, 在它的子元素之前, 可以先画背景
+// VisitElement:    普通的元素
+// VisitGroupEnd:   离开 Group, 在它的子元素之后
+type VisitKind uint8
+
+const (
+	VisitGroupBegin VisitKind = iota
+	VisitElement
+	VisitGroupEnd
+)
+
+type VisitEvent struct {
+	Kind VisitKind
+	ElementInfo
+}
+
+// 按层级访问所有元素(不包括默认的根布局), 每个 Group 先输出 VisitGroupBegin,
+// 然后是它的子元素(按 Group 中排列的顺序, 见 SetSortKey), 最后是 VisitGroupEnd, 两者的 Bound 相同.
+// 不在 Group 中排列的元素(比如 PlaceAt 和拖动中的元素)当作根布局的子元素. 选项和 Visit 一样
+func (lyt *LayoutManager) VisitTree(fn func(ev VisitEvent), opts ...VisitOption) {
+	var opt VisitOption
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	var walk func(elem *Element)
+	walk = func(elem *Element) {
+		if opt.SkipEmpty && lyt.inEmptyGroup(elem) {
+			return
+		}
+		info := lyt.info(elem)
+		if !elem.group {
+			fn(VisitEvent{VisitElement, info})
+			return
+		}
+		fn(VisitEvent{VisitGroupBegin, info})
+		for _, ii := range elem.kids {
+			if ii < len(lyt.uiElements) {
+				walk(&lyt.uiElements[ii])
+			}
+		}
+		fn(VisitEvent{VisitGroupEnd, info})
+	}
+	for i := 1; i < len(lyt.uiElements); i++ {
+		elem := &lyt.uiElements[i]
+		if p, ok := lyt.Element(elem.parent); !ok || p == elem || p.parent < 0 {
+			walk(elem)
+		}
+	}
+}
+
+// 元素本身或者某个上层是面积为 0 的 Group
+func (lyt *LayoutManager) inEmptyGroup(elem *Element) bool {
+	for depth := 0; elem != nil && depth < len(lyt.uiElements); depth++ {
+		if elem.group && elem.Bound.Empty() && elem.parent >= 0 {
+			return true
+		}
+		parent, ok := lyt.Element(elem.parent)
+		if !ok || parent == elem {
+			break
+		}
+		elem = parent
+	}
+	return false
+}
+
+func (lyt *LayoutManager) info(elem *Element) ElementInfo {
+	b := lyt.absBound(elem)
+	if lyt.pixelSnap {
+		b = lyt.snap(b)
+	}
+	if lyt.hasPrecision {
+		b = lyt.roundBound(b)
+	}
+	info := ElementInfo{
+		ID: elem.id,
+		Bound: b,
+		Enabled: !elem.disabled,
+		Opacity: lyt.opacity(elem),
+		Clip: elem.clip,
+		Clipped: elem.clipped,
+		CornerRadius: elem.clipRadius,
+	}
+	if elem.selfClip {
+		if info.Clipped {
+			info.Clip = info.Clip.Intersect(b)
+		} else {
+			info.Clip, info.Clipped = b, true
+		}
+		if elem.radius > 0 {
+			info.CornerRadius = elem.radius
+		}
+	}
+	if lyt.hasPrecision {
+		info.Clip = lyt.roundBound(info.Clip)
+	}
+	return info
+}
+
+// 沿着 parent 向上累乘不透明度
+func (lyt *LayoutManager) opacity(elem *Element) float32 {
+	alpha := float32(1)
+	for depth := 0; elem != nil && depth < len(lyt.uiElements); depth++ {
+		if elem.hasOpacity {
+			alpha *= elem.opacity
+		}
+		parent, ok := lyt.Element(elem.parent)
+		if !ok || parent == elem {
+			break
+		}
+		elem = parent
+	}
+	return alpha
+}
+
+// 设置设备像素和布局单位的比例(比如 Retina 屏幕为 2)
+func (lyt *LayoutManager) SetScale(scale float32) {
+	lyt.scale = scale
+}
+
+// 开启后 Visit/Export 输出的坐标对齐到设备像素, 布局计算仍然使用浮点数
+func (lyt *LayoutManager) SetPixelSnap(snap bool) {
+	lyt.pixelSnap = snap
+}
+
+// Visit/Export 输出的坐标保留 decimals 位小数, 这样布局没有变化时, 浮点误差不会让
+// 每一帧的输出都不同(比如定点数的顶点缓冲). decimals < 0 时关闭
+func (lyt *LayoutManager) SetExportPrecision(decimals int) {
+	lyt.precision, lyt.hasPrecision = decimals, decimals >= 0
+}
+
+func (lyt *LayoutManager) roundBound(b Bound) Bound {
+	p := geo.Pow10(lyt.precision)
+	round := func(v float32) float32 {
+		return float32(geo.Floor(float64(v)*p+.5) / p)
+	}
+	return Bound{round(b.X), round(b.Y), round(b.W), round(b.H)}
+}
+
+// 对齐四条边而不是对齐大小, 这样相邻的元素之间不会出现缝隙或者重叠
+func (lyt *LayoutManager) snap(b Bound) Bound {
+	scale := lyt.scale
+	if scale <= 0 {
+		scale = 1
+	}
+	round := func(v float32) float32 {
+		return float32(geo.Floor(float64(v*scale)+.5)) / scale
+	}
+	x0, y0 := round(b.X), round(b.Y)
+	x1, y1 := round(b.X+b.W), round(b.Y+b.H)
+	return Bound{x0, y0, x1 - x0, y1 - y0}
+}
+
+// 缓存当前根布局的所有元素, key 由使用者根据自己的状态计算
+type layoutCache struct {
+	key      uint64
+	valid    bool
+	hit      bool
+	elements []Element
+}
+
+// 如果 key 和上一次相同，返回 true，调用者应该跳过布局代码, EndCached 会恢复缓存的元素.
+// 否则正常布局，在 EndCached 里更新缓存. EndCached 之后 Export/Visit/VisitTree/HitTest
+// 等所有的查询都使用恢复后的元素, 和重新布局的结果一样.
+//
+//	if !lyt.BeginCached(key) {
+//		... layout
+//	}
+//	lyt.EndCached()
+func (lyt *LayoutManager) BeginCached(key uint64) (cached bool) {
+	c := &lyt.cache
+	c.hit = c.valid && c.key == key
+	c.key = key
+	return c.hit
+}
+
+func (lyt *LayoutManager) EndCached() {
+	c := &lyt.cache
+	if !c.hit {
+		c.elements = append(c.elements[:0], lyt.uiElements...)
+		c.valid = true
+		return
+	}
+	c.hit = false
+	lyt.uiElements = append(lyt.uiElements[:0], c.elements...)
+	if lyt.ids == nil {
+		lyt.ids = make(map[ID]int)
+	}
+	for k := range lyt.ids {
+		delete(lyt.ids, k)
+	}
+	for i := range lyt.uiElements {
+		lyt.ids[lyt.uiElements[i].id] = i
+	}
+	// 打开的 Group(一般只有根布局)指向恢复后的元素
+	for i := range lyt.groupStack {
+		g := &lyt.groupStack[i]
+		if ii := lyt.index(g.id); ii >= 0 {
+			g.Element = &lyt.uiElements[ii]
+		}
+	}
+}
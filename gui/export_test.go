@@ -0,0 +1,291 @@
+package gui
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestBeginCached(t *testing.T) {
+	lyt := New()
+
+	var (
+		builds int
+		width  float32 = 10
+	)
+	frame := func(key uint64) {
+		if !lyt.BeginCached(key) {
+			builds++
+			elem, ok := lyt.Element(1)
+			if !ok {
+				elem = lyt.NewElement(1)
+			}
+			elem.Size(width, 10)
+		}
+		lyt.EndCached()
+	}
+
+	frame(1)
+	width = 20
+	frame(1)
+	if builds != 1 {
+		t.Error("layout should be skipped with the same key, builds:", builds)
+	}
+	if ex := lyt.Export(); len(ex) != 1 || ex[0].ID != 1 || ex[0].W != 10 {
+		t.Error("cached export:", ex)
+	}
+
+	frame(2)
+	if builds != 2 {
+		t.Error("layout should be recomputed with a new key, builds:", builds)
+	}
+	if ex := lyt.Export(); len(ex) != 1 || ex[0].W != 20 {
+		t.Error("recomputed export:", ex)
+	}
+
+	// BeginFrame 清空了元素, 命中缓存时 EndCached 恢复它们, 所有的查询都能看到
+	lyt.BeginFrame()
+	frame(2)
+	if builds != 2 {
+		t.Error("layout should be skipped with the same key, builds:", builds)
+	}
+	if id, ok := lyt.HitTest(mgl32.Vec2{15, 5}); !ok || id != 1 {
+		t.Error("hit test should see the cached layout:", id, ok)
+	}
+	if lyt.cache.hit {
+		t.Error("EndCached should clear the hit flag")
+	}
+	elem, _ := lyt.Element(1)
+	elem.Size(30, 10)
+	if ex := lyt.Export(); len(ex) != 1 || ex[0].W != 30 {
+		t.Error("export without a new BeginCached should be live:", ex)
+	}
+}
+
+func TestPixelSnap(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func() {
+		lyt.Move(10.3, 0.2)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		for id := ID(2); id <= 3; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(20.4, 10.6)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+	lyt.SetPixelSnap(true)
+
+	ex := lyt.Export()
+	for _, info := range ex {
+		b := info.Bound
+		for _, v := range []float32{b.X, b.Y, b.W, b.H} {
+			if v != float32(int(v)) {
+				t.Error("snapped bound should be integer at scale 1:", b)
+			}
+		}
+	}
+	// adjacent elements share the same edge
+	if a, b := ex[1].Bound, ex[2].Bound; a.X+a.W != b.X {
+		t.Error("gap between snapped elements:", a, b)
+	}
+
+	lyt.SetScale(2)
+	ex = lyt.Export()
+	// x: 10.3*2 = 20.6 -> 21 -> 10.5, right edge: 30.7*2 = 61.4 -> 61 -> 30.5
+	if b := ex[1].Bound; b.X != 10.5 || b.Y != 0 || b.W != 20 || b.H != 11 {
+		t.Error("snapped bound at scale 2:", b)
+	}
+}
+
+func TestVisitSkipEmpty(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		// empty group
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 2))
+		lyt.EndLayout()
+		elem, _ := lyt.BeginElement(3)
+		elem.Size(10, 10)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	visit := func(opt VisitOption) map[ID]Bound {
+		visited := map[ID]Bound{}
+		lyt.Visit(func(info ElementInfo) {
+			visited[info.ID] = info.Bound
+		}, opt)
+		return visited
+	}
+	if visited := visit(VisitOption{}); len(visited) != 3 || visited[2].W != 0 || visited[2].H != 0 {
+		t.Error("empty group should be visited as 0x0:", visited)
+	}
+	if visited := visit(VisitOption{SkipEmpty: true}); len(visited) != 2 {
+		t.Error("empty group should be skipped:", visited)
+	} else if _, ok := visited[2]; ok {
+		t.Error("empty group should be skipped:", visited)
+	}
+}
+
+func TestOpacity(t *testing.T) {
+	lyt := New()
+
+	half := NewProperty()
+	half.Opacity = .5
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(10, 10)
+		lyt.EndElement(elem)
+		elem, _ = lyt.BeginElement(3)
+		elem.Size(10, 10)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	lyt.SetProperty(1, half)
+	lyt.SetProperty(2, half)
+	frame()
+
+	opacity := map[ID]float32{}
+	lyt.Visit(func(info ElementInfo) {
+		opacity[info.ID] = info.Opacity
+	})
+	if opacity[1] != .5 {
+		t.Error("group opacity:", opacity[1])
+	}
+	if opacity[2] != .25 {
+		t.Error("child should inherit group opacity:", opacity[2])
+	}
+	if opacity[3] != .5 {
+		t.Error("child without opacity:", opacity[3])
+	}
+
+	// NewProperty 创建的属性是不透明的
+	lyt.SetProperty(2, NewProperty())
+	frame()
+	lyt.Visit(func(info ElementInfo) {
+		opacity[info.ID] = info.Opacity
+	})
+	if opacity[2] != .5 {
+		t.Error("default property should be opaque:", opacity[2])
+	}
+}
+
+func TestExportPrecision(t *testing.T) {
+	lyt := New()
+	lyt.SetExportPrecision(2)
+
+	export := func(noise float32) string {
+		lyt.Move(10+noise, 20-noise)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(33.333+noise, 12)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+		return fmt.Sprint(lyt.Export())
+	}
+	export(0)
+	a, b := export(0), export(.0003)
+	if a != b {
+		t.Error("noise below the precision should not change the output:", a, b)
+	}
+	if c := export(.02); c == a {
+		t.Error("changes above the precision should be exported")
+	}
+}
+
+func TestVisitTree(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		for id := ID(2); id <= 3; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(10, 10)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	type event struct {
+		kind VisitKind
+		id ID
+	}
+	var events []event
+	lyt.VisitTree(func(ev VisitEvent) {
+		events = append(events, event{ev.Kind, ev.ID})
+		if ev.Kind != VisitElement && ev.Bound != (Bound{0, 0, 10, 20}) {
+			t.Error("group events should carry the group bound:", ev.Bound)
+		}
+	})
+	expect := []event{{VisitGroupBegin, 1}, {VisitElement, 2}, {VisitElement, 3}, {VisitGroupEnd, 1}}
+	if len(events) != len(expect) {
+		t.Fatal("event order:", events)
+	}
+	for i := range expect {
+		if events[i] != expect[i] {
+			t.Error("event order:", events)
+			break
+		}
+	}
+}
+
+func TestVisitTreeOptions(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+	frame := func() {
+		if !lyt.BeginCached(1) {
+			lyt.Move(0, 0)
+			lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+			lyt.SetSortKey(2, 1)
+			elem, _ := lyt.BeginElement(2)
+			elem.Size(10, 10)
+			lyt.EndElement(elem)
+			elem, _ = lyt.BeginElement(3)
+			elem.Size(10, 10)
+			lyt.EndElement(elem)
+			// 面积为 0 的 Group
+			lyt.PushLayout(LinearVertical, layoutOf(lyt, 4))
+			elem, _ = lyt.BeginElement(5)
+			lyt.EndElement(elem)
+			lyt.EndLayout()
+			lyt.EndLayout()
+		}
+		lyt.EndCached()
+	}
+	frame()
+	frame()
+
+	tree := func(opts ...VisitOption) (ids []ID) {
+		lyt.VisitTree(func(ev VisitEvent) {
+			if ev.Kind != VisitGroupEnd {
+				ids = append(ids, ev.ID)
+			}
+		}, opts...)
+		return
+	}
+	// 命中缓存之后和重新布局的结果一样, 按排列的顺序
+	if ids := tree(); !reflect.DeepEqual(ids, []ID{1, 3, 4, 5, 2}) {
+		t.Error("unexpected tree order:", ids)
+	}
+	if ids := tree(VisitOption{SkipEmpty: true}); !reflect.DeepEqual(ids, []ID{1, 3, 2}) {
+		t.Error("empty groups should be skipped:", ids)
+	}
+}
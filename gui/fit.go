@@ -0,0 +1,72 @@
+package gui
+
+// 图片等内容在元素中的缩放方式
+// FitStretch: 拉伸到元素的大小, 不保持比例
+// FitContain: 保持比例, 完整显示在元素内, 居中
+// FitCover:   保持比例, 填满元素, 超出的部分需要裁掉, 居中
+type FitMode uint8
+
+const (
+	FitStretch FitMode = iota
+	FitContain
+	FitCover
+)
+
+// 设置元素内容的缩放方式, 内容的原始大小由 SetMeasure 的测量函数提供
+func (lyt *LayoutManager) SetFit(id ID, mode FitMode) {
+	elem := lyt.obtain(id)
+	elem.fit, elem.hasFit = mode, true
+}
+
+// 元素内容实际绘制的区域(绝对坐标), FitCover 时会超出元素的范围
+// 元素没有设置 SetFit 或者测量函数时返回元素本身的区域
+func (lyt *LayoutManager) FitBound(id ID) (b Bound, ok bool) {
+	elem, ok := lyt.Element(id)
+	if !ok {
+		return
+	}
+	b = lyt.absBound(elem)
+	if elem.hasFit && elem.measure != nil {
+		w, h := elem.measure(0, 0)
+		b = FitRect(elem.fit, w, h, b)
+	}
+	return
+}
+
+// 表格中按 SetCellFit 摆放的内容: dst 是单元格中实际显示的区域(绝对坐标),
+// src 是内容(测量函数返回的大小)中被显示的部分, FitCover 时是裁剪后的区域
+func (lyt *LayoutManager) CellCrop(id ID) (src, dst Bound, ok bool) {
+	var (
+		elem *Element
+		cell Bound
+	)
+	if elem, ok = lyt.Element(id); !ok || !elem.hasFit || elem.measure == nil {
+		return src, dst, false
+	}
+	if cell, ok = lyt.CellOf(id); !ok {
+		return
+	}
+	w, h := elem.measure(0, 0)
+	if w <= 0 || h <= 0 {
+		return src, cell, true
+	}
+	r := FitRect(elem.fit, w, h, cell)
+	dst = r.Intersect(cell)
+	sx, sy := r.W/w, r.H/h
+	src = Bound{(dst.X - r.X) / sx, (dst.Y - r.Y) / sy, dst.W / sx, dst.H / sy}
+	return
+}
+
+// 把 w*h 的内容按 mode 放到 box 中
+func FitRect(mode FitMode, w, h float32, box Bound) Bound {
+	if mode == FitStretch || w <= 0 || h <= 0 {
+		return box
+	}
+	sx, sy := box.W/w, box.H/h
+	scale := sx
+	if (mode == FitContain) == (sy < sx) {
+		scale = sy
+	}
+	w, h = w*scale, h*scale
+	return Bound{box.X + (box.W-w)/2, box.Y + (box.H-h)/2, w, h}
+}
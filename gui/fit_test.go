@@ -0,0 +1,76 @@
+package gui
+
+import (
+	"testing"
+)
+
+func TestFitRect(t *testing.T) {
+	box := Bound{10, 10, 144, 144}
+	cases := []struct {
+		mode   FitMode
+		expect Bound
+	}{
+		{FitStretch, Bound{10, 10, 144, 144}},
+		{FitContain, Bound{10, 41.5, 144, 81}},
+		{FitCover, Bound{-46, 10, 256, 144}},
+	}
+	for _, c := range cases {
+		if b := FitRect(c.mode, 16, 9, box); b != c.expect {
+			t.Error("fit mode", c.mode, ":", b, "expected:", c.expect)
+		}
+	}
+}
+
+func TestFitBound(t *testing.T) {
+	lyt := New()
+	lyt.SetMeasure(2, func(maxW, maxH float32) (w, h float32) {
+		return 160, 90
+	})
+	lyt.SetFit(2, FitContain)
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.Cursor.SetSize(144, 144).To(2)
+		elem, _ := lyt.BeginElement(2)
+		lyt.EndElement(elem)
+	}
+	frame()
+	frame()
+
+	if b, _ := lyt.FitBound(2); b != (Bound{0, 31.5, 144, 81}) {
+		t.Error("contained image:", b)
+	}
+}
+
+func TestCellCrop(t *testing.T) {
+	lyt := New()
+	lyt.SetMeasure(2, func(maxW, maxH float32) (w, h float32) {
+		return 200, 100
+	})
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(GridLayout, layoutOf(lyt, 1))
+		lyt.SetColumnSizes([]TrackSize{Fixed(100)}).SetRowSizes([]TrackSize{Fixed(100)})
+		lyt.SetCellFit(FitCover)
+		elem, _ := lyt.BeginElement(2)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	src, dst, ok := lyt.CellCrop(2)
+	if !ok {
+		t.Fatal("cell crop not found")
+	}
+	if dst != (Bound{0, 0, 100, 100}) {
+		t.Error("cover should fill the cell:", dst)
+	}
+	if src != (Bound{50, 0, 100, 100}) {
+		t.Error("wide image should be cropped at both sides:", src)
+	}
+	if e, _ := lyt.Element(2); e.Bound != (Bound{0, 0, 100, 100}) {
+		t.Error("element should fill the cell:", e.Bound)
+	}
+}
@@ -0,0 +1,46 @@
+package gui
+
+import (
+	"korok.io/korok/engi/math"
+)
+
+// 流式布局: 子元素从左到右排列, 超出 Group 的宽度时换行, 行高取这一行最高的元素,
+// 元素在行内按 Gravity 垂直对齐. Group 没有设置大小时只有一行
+type flowLayout struct {}
+
+// 在 EndLayout 时统一摆放
+func (flowLayout) Measure(g *Group, elem *Element) {}
+
+func (flowLayout) Place(g *Group, elem *Element, c *Bound) {}
+
+func (flowLayout) Arrange(g *Group, children []*Element) {
+	var (
+		availW, _ = g.Content()
+		row []*Element
+		x, y, rowH, w float32
+	)
+	// 摆放一行, 并移到下一行
+	flush := func() {
+		for _, c := range row {
+			_, dy := g.Extent(c)
+			c.Y = g.Padding.Top + y + c.Top + (rowH-dy)*g.gravityOf(c).Y
+		}
+		if len(row) > 0 {
+			w = math.Max(w, x-g.Spacing)
+			y += rowH + g.Spacing
+		}
+		row, x, rowH = row[:0], 0, 0
+	}
+	for _, c := range children {
+		dx, dy := g.Extent(c)
+		if g.hasSize && len(row) > 0 && x+dx > availW {
+			flush()
+		}
+		c.X = g.Padding.Left + x + c.Left
+		x += dx + g.Spacing
+		rowH = math.Max(rowH, dy)
+		row = append(row, c)
+	}
+	flush()
+	g.Size.W, g.Size.H = w, math.Max(y-g.Spacing, 0)
+}
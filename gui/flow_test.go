@@ -0,0 +1,38 @@
+package gui
+
+import "testing"
+
+func TestFlowAspect(t *testing.T) {
+	lyt := New()
+
+	// 三张高度相同, 比例不同的图片
+	ratios := map[ID]float32{2: 1.5, 3: .5, 4: 2}
+	for id, r := range ratios {
+		lyt.SetAspectRatio(id, r)
+		lyt.SetAspectHeight(id, 40)
+	}
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(FlowLayout, layoutOf(lyt, 1))
+		lyt.SetSize(100, 200)
+		lyt.SetSpacing(0)
+		for id := ID(2); id <= 4; id++ {
+			elem, _ := lyt.BeginElement(id)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	expect := map[ID]Bound{
+		2: {0, 0, 60, 40},
+		3: {60, 0, 20, 40},
+		4: {0, 40, 80, 40}, // 60+20+80 > 100, 换行
+	}
+	for id, b := range expect {
+		if e, _ := lyt.Element(id); e.Bound != b {
+			t.Error("image", id, "placed at:", e.Bound, "expected:", b)
+		}
+	}
+}
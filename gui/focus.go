@@ -0,0 +1,61 @@
+package gui
+
+// 焦点变化时产生的事件
+type FocusEvent struct {
+	ID      ID
+	Focused bool
+}
+
+type focusState struct {
+	id     ID
+	ok     bool
+	events []FocusEvent
+}
+
+// 设置焦点元素, 原来的焦点元素产生失去焦点的事件
+func (lyt *LayoutManager) SetFocus(id ID) {
+	f := &lyt.focus
+	if f.ok && f.id == id {
+		return
+	}
+	lyt.ClearFocus()
+	f.id, f.ok = id, true
+	f.events = append(f.events, FocusEvent{ID: id, Focused: true})
+}
+
+func (lyt *LayoutManager) ClearFocus() {
+	f := &lyt.focus
+	if f.ok {
+		f.events = append(f.events, FocusEvent{ID: f.id, Focused: false})
+		f.ok = false
+	}
+}
+
+func (lyt *LayoutManager) Focused() (id ID, ok bool) {
+	return lyt.focus.id, lyt.focus.ok
+}
+
+// 本帧产生的焦点事件(包括 EndFrame 中清除焦点的事件), BeginFrame 时清空
+func (lyt *LayoutManager) FocusEvents() []FocusEvent {
+	return lyt.focus.events
+}
+
+// 本帧没有声明的元素不能再保持焦点或者悬停状态, 在 EndFrame 中按本帧所有根布局的元素检查,
+// 清除时产生失去焦点/离开的事件
+func (lyt *LayoutManager) dropStale() {
+	if f := &lyt.focus; f.ok && !lyt.declaredAnywhere(f.id) {
+		lyt.ClearFocus()
+	}
+	if h := &lyt.hover; h.ok && !lyt.declaredAnywhere(h.id) {
+		h.events = append(h.events[:0], HoverEvent{ID: h.id, Entered: false})
+		h.ok, h.frames = false, 0
+	}
+}
+
+// 任意一个根布局中有这个元素
+func (lyt *LayoutManager) declaredAnywhere(id ID) (found bool) {
+	lyt.eachLayer(func() {
+		found = found || lyt.index(id) >= 0
+	})
+	return
+}
@@ -0,0 +1,38 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestDropStaleFocus(t *testing.T) {
+	lyt := New()
+
+	frame := func(ids ...ID) {
+		lyt.BeginFrame()
+		lyt.Move(0, 0)
+		for _, id := range ids {
+			lyt.Cursor.SetSize(20, 20).To(id)
+			elem, _ := lyt.BeginElement(id)
+			lyt.EndElement(elem)
+		}
+		lyt.EndFrame()
+	}
+	frame(2)
+	frame(2)
+	lyt.SetFocus(2)
+	lyt.Hovered(mgl32.Vec2{10, 10})
+
+	// screen change, 2 is gone
+	frame(3)
+	if _, ok := lyt.Focused(); ok {
+		t.Error("focus on a removed element should be cleared")
+	}
+	if events := lyt.FocusEvents(); len(events) != 1 || events[0] != (FocusEvent{2, false}) {
+		t.Error("focus events:", events)
+	}
+	if events := lyt.HoverEvents(); len(events) != 1 || events[0] != (HoverEvent{2, false}) {
+		t.Error("hover events:", events)
+	}
+}
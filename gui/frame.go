@@ -0,0 +1,146 @@
+package gui
+
+// 每一帧的开始和结束, 应该成对调用:
+//
+//	if !lyt.BeginFrame() {
+//		... layout
+//	}
+//	lyt.EndFrame()
+//
+// BeginFrame 清空元素列表并重新建立默认的根布局, 这样没有再声明的元素不会一直累积.
+// 上一帧的元素会被保存下来, 本帧再次声明时恢复它的大小和属性, 所以布局结果和
+// 不调用 Reset 时一样. 上一帧的绝对坐标可以用 PrevBound 查询.
+//
+// 如果布局被冻结(SetFrozen), 什么也不做并返回 true, 调用者应该跳过布局代码,
+// Export/Visit/HitTest 继续使用冻结时的结果. 调用过 OnResize 之后的一帧除外.
+func (lyt *LayoutManager) BeginFrame() (frozen bool) {
+	if lyt.frozen && !lyt.resize.pending {
+		return true
+	}
+	lyt.framed = true
+	lyt.focus.events = lyt.focus.events[:0]
+	lyt.eachLayer(lyt.beginLayer)
+	lyt.rootChanged = false
+	lyt.autoSeq = 0
+	lyt.clips = lyt.clips[:0]
+	lyt.cache.hit = false
+	return false
+}
+
+// 每一个根布局都要清空, 否则不再声明的元素会一直留在没有激活的根布局中
+func (lyt *LayoutManager) beginLayer() {
+	if lyt.prevBounds == nil {
+		lyt.prevBounds = make(map[ID]Bound)
+	}
+	for k := range lyt.prevBounds {
+		delete(lyt.prevBounds, k)
+	}
+	for i := 1; i < len(lyt.uiElements) && !lyt.rootChanged; i++ {
+		elem := &lyt.uiElements[i]
+		lyt.prevBounds[elem.id] = lyt.absBound(elem)
+	}
+	lyt.prev = append(lyt.prev[:0], lyt.uiElements...)
+	if lyt.prevIndex == nil {
+		lyt.prevIndex = make(map[ID]int)
+	}
+	for k := range lyt.prevIndex {
+		delete(lyt.prevIndex, k)
+	}
+	for i := len(lyt.prev) - 1; i >= 0; i-- {
+		lyt.prevIndex[lyt.prev[i].id] = i
+	}
+
+	lyt.Reset()
+	lyt.groupStack = lyt.groupStack[:0]
+	lyt.Cursor.Reset()
+	lyt.Cursor.X, lyt.Cursor.Y = 0, 0
+
+	// 根布局保留上一帧的状态
+	root := lyt.NewElement(0)
+	if len(lyt.prev) > 0 {
+		*root = lyt.prev[0]
+	}
+	lyt.pushRoot(root)
+}
+
+// 结束一帧, 丢弃本帧没有声明的元素
+func (lyt *LayoutManager) EndFrame() {
+	if lyt.frozen && !lyt.resize.pending {
+		return
+	}
+	lyt.eachLayer(func() {
+		lyt.assert(len(lyt.groupStack) <= 1, "unbalanced group stack at EndFrame, %d group(s) not ended", len(lyt.groupStack)-1)
+		lyt.prev = lyt.prev[:0]
+		for k := range lyt.prevIndex {
+			delete(lyt.prevIndex, k)
+		}
+	})
+	lyt.dropStale()
+	lyt.emitResize()
+}
+
+// 冻结布局, 比如暂停时只显示一个静态的菜单, 不需要每一帧重新计算
+// 解冻后下一次 BeginFrame 恢复正常的布局
+func (lyt *LayoutManager) SetFrozen(frozen bool) {
+	lyt.frozen = frozen
+}
+
+func (lyt *LayoutManager) Frozen() bool {
+	return lyt.frozen
+}
+
+// 元素在当前根布局中上一帧的绝对坐标
+func (lyt *LayoutManager) PrevBound(id ID) (b Bound, ok bool) {
+	b, ok = lyt.prevBounds[id]
+	return
+}
+
+// 从上一帧的元素中恢复, 按 BeginFrame 建立的 prevIndex 查找
+func (lyt *LayoutManager) revive(id ID) (elem *Element, ok bool) {
+	i, ok := lyt.prevIndex[id]
+	if !ok || i >= len(lyt.prev) {
+		return nil, false
+	}
+	elem = lyt.NewElement(id)
+	*elem = lyt.prev[i]
+	// 上一帧的索引已经没有意义了
+	elem.kids = nil
+	return elem, true
+}
+
+// 保留模式(retained)的两阶段接口:
+//
+//	lyt.Declare(func() {
+//		... 和立即模式一样声明布局
+//	})
+//	w, h := lyt.Measure()
+//	lyt.Arrange(Bound{0, 0, w, h})
+//
+// Declare 记录声明布局的函数, Measure 只计算大小, 不修改当前的布局结果,
+// Arrange 把根布局放到 root 中, 完成一帧完整的布局(BeginFrame/EndFrame).
+// 元素第一次声明时还没有大小(见 BeginElement), 所以第一次 Measure 之前应该先 Arrange 一次.
+// 立即模式每一帧直接声明布局, 不需要这些
+func (lyt *LayoutManager) Declare(fn func()) {
+	lyt.tree = fn
+}
+
+func (lyt *LayoutManager) Measure() (w, h float32) {
+	if lyt.tree == nil {
+		return
+	}
+	return lyt.MeasureOnly(lyt.tree)
+}
+
+func (lyt *LayoutManager) Arrange(root Bound) {
+	if lyt.tree == nil {
+		return
+	}
+	lyt.SetRootSize(root.W, root.H)
+	if lyt.BeginFrame() {
+		return
+	}
+	r := &lyt.groupStack[0]
+	r.X, r.Y = finite(root.X), finite(root.Y)
+	lyt.tree()
+	lyt.EndFrame()
+}
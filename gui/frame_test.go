@@ -0,0 +1,177 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestBeginFrame(t *testing.T) {
+	lyt := New()
+
+	frame := func(ids ...ID) {
+		lyt.BeginFrame()
+		lyt.Move(10, 10)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		for _, id := range ids {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(20, 20)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+		lyt.EndFrame()
+	}
+	frame(2, 3)
+	frame(2, 3)
+	frame(2, 3)
+
+	if n := len(lyt.uiElements); n != 4 {
+		t.Error("elements should not accumulate:", n)
+	}
+	if elem, _ := lyt.Element(3); elem.Y != 20+lyt.spacing {
+		t.Error("element should keep its state across frames:", elem.Bound)
+	}
+
+	frame(2)
+	if _, ok := lyt.Element(3); ok {
+		t.Error("element not declared in this frame should be dropped")
+	}
+	if b, ok := lyt.PrevBound(3); !ok || b != (Bound{10, 34, 20, 20}) {
+		t.Error("prev bound:", b, ok)
+	}
+}
+
+func TestSetFrozen(t *testing.T) {
+	lyt := New()
+
+	var width float32 = 20
+	frame := func() {
+		if !lyt.BeginFrame() {
+			lyt.Move(0, 0)
+			lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+			elem, _ := lyt.BeginElement(2)
+			elem.Size(width, 20)
+			lyt.EndElement(elem)
+			lyt.EndLayout()
+		}
+		lyt.EndFrame()
+	}
+	frame()
+	frame()
+	lyt.SetFrozen(true)
+
+	width = 50
+	frame()
+	frame()
+	if elem, _ := lyt.Element(2); elem.W != 20 {
+		t.Error("frozen layout should not change:", elem.Bound)
+	}
+	if id, ok := lyt.HitTest(mgl32.Vec2{10, 10}); !ok || id != 2 {
+		t.Error("hit test against frozen bounds:", id, ok)
+	}
+
+	lyt.SetFrozen(false)
+	frame()
+	if elem, _ := lyt.Element(2); elem.W != 50 {
+		t.Error("layout should update after unfreeze:", elem.Bound)
+	}
+}
+
+// 不依赖渲染器, 完整地运行一帧
+func TestHeadlessFrame(t *testing.T) {
+	lyt := New()
+	lyt.SetRootSize(320, 240)
+
+	frame := func() {
+		lyt.BeginFrame()
+		lyt.Move(10, 20)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		lyt.SetSpacing(0)
+		for id := ID(2); id <= 3; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(30, 15)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+		lyt.EndFrame()
+	}
+	frame()
+	frame()
+
+	if b, _ := lyt.BoundsOf(1); b != (Bound{10, 20, 60, 15}) {
+		t.Error("group bound:", b)
+	}
+	if b, _ := lyt.BoundsOf(3); b != (Bound{40, 20, 30, 15}) {
+		t.Error("element bound:", b)
+	}
+}
+
+func TestAutoElement(t *testing.T) {
+	lyt := New()
+
+	frame := func() (ids []ID) {
+		lyt.BeginFrame()
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		for i := 0; i < 3; i++ {
+			id, elem, _ := lyt.AutoElement()
+			elem.Size(10, 10)
+			lyt.EndElement(elem)
+			ids = append(ids, id)
+		}
+		lyt.EndLayout()
+		lyt.EndFrame()
+		return
+	}
+	first := frame()
+	for i, id := range first {
+		if id != AutoIDBase+ID(i) {
+			t.Error("ids should be sequential:", first)
+		}
+		if !lyt.Has(id) {
+			t.Error("auto element should be registered:", id)
+		}
+	}
+	if second := frame(); second[0] != first[0] || second[2] != first[2] {
+		t.Error("counter should reset each frame:", first, second)
+	}
+	if b, _ := lyt.BoundsOf(first[2]); b.H != 10 {
+		t.Error("auto elements should keep their size across frames:", b)
+	}
+}
+
+func TestMeasureArrange(t *testing.T) {
+	lyt := New()
+	lyt.Declare(func() {
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		lyt.SetSpacing(0)
+		for id := ID(2); id <= 3; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(40, 20)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+
+		// 在根布局中居中
+		lyt.Cursor.SetGravity(.5, .5).To(4)
+		elem, _ := lyt.BeginElement(4)
+		elem.Size(10, 10)
+		lyt.EndElement(elem)
+	})
+	lyt.Arrange(Bound{0, 0, 100, 100})
+
+	if w, h := lyt.Measure(); w != 80 || h != 20 {
+		t.Error("measured size:", w, h)
+	}
+
+	lyt.Arrange(Bound{0, 0, 100, 100})
+	if b, _ := lyt.BoundsOf(4); b != (Bound{45, 45, 10, 10}) {
+		t.Error("centered in the first root:", b)
+	}
+	lyt.Arrange(Bound{20, 10, 200, 50})
+	if b, _ := lyt.BoundsOf(4); b != (Bound{115, 30, 10, 10}) {
+		t.Error("centered in the second root:", b)
+	}
+	if b, _ := lyt.BoundsOf(3); b != (Bound{60, 10, 40, 20}) {
+		t.Error("group should follow the root origin:", b)
+	}
+}
@@ -0,0 +1,220 @@
+package gui
+
+import (
+	"korok.io/korok/engi/math"
+)
+
+// 表格的行或者列的大小
+// TrackFixed:    固定大小
+// TrackAuto:     由内容决定
+// TrackFraction: 按比例分配剩余的空间(Group 需要设置大小, 否则同 TrackAuto)
+type TrackType uint8
+
+const (
+	TrackAuto TrackType = iota
+	TrackFixed
+	TrackFraction
+)
+
+type TrackSize struct {
+	Type  TrackType
+	Value float32
+}
+
+func Fixed(px float32) TrackSize {
+	return TrackSize{TrackFixed, px}
+}
+
+func Fraction(fr float32) TrackSize {
+	return TrackSize{TrackFraction, fr}
+}
+
+func Auto() TrackSize {
+	return TrackSize{TrackAuto, 0}
+}
+
+type gridOption struct {
+	columns, rows []TrackSize
+	square bool
+	// 单元格内容的缩放方式, 见 SetCellFit
+	fit FitMode
+	hasFit bool
+}
+
+// 设置当前表格每一列的大小, 列数等于 len(sizes)
+func (lyt *LayoutManager) SetColumnSizes(sizes []TrackSize) *LayoutManager {
+	lyt.hGroup.grid.columns = sizes
+	return lyt
+}
+
+// 设置当前表格每一行的大小, 多出来的行按 TrackAuto 计算
+func (lyt *LayoutManager) SetRowSizes(sizes []TrackSize) *LayoutManager {
+	lyt.hGroup.grid.rows = sizes
+	return lyt
+}
+
+// 单元格的高度等于宽度(比如相册), 先计算列宽, 行高取这一行最宽的列, 忽略 SetRowSizes
+func (lyt *LayoutManager) SetSquareCells(square bool) *LayoutManager {
+	lyt.hGroup.grid.square = square
+	return lyt
+}
+
+// 照片墙之类的自动列数: 单元格拉伸到填满 availableWidth, 选出单元格大小最接近 targetCell 的列数,
+// 这样拉伸(或者裁剪)的部分最少. 可以配合 SetColumnSizes(Fraction(1)...) 使用, 至少一列
+func AutoColumns(targetCell, availableWidth float32) int {
+	if targetCell <= 0 || availableWidth <= targetCell {
+		return 1
+	}
+	n := int(availableWidth / targetCell)
+	// 比较两种列数下单元格的缩放比例
+	scale := func(n int) float32 {
+		w := availableWidth / float32(n)
+		return math.Max(w/targetCell, targetCell/w)
+	}
+	if scale(n+1) < scale(n) {
+		n++
+	}
+	return n
+}
+
+// 单元格内容(设置了 SetMeasure 的元素, 比如缩略图)按 mode 放到单元格中, 覆盖元素自己的 SetFit:
+// FitContain 时元素的大小是缩放后的内容, 其它方式下元素填满单元格
+// 裁剪的区域见 CellCrop
+func (lyt *LayoutManager) SetCellFit(mode FitMode) *LayoutManager {
+	lyt.hGroup.grid.fit, lyt.hGroup.grid.hasFit = mode, true
+	return lyt
+}
+
+// 元素所在单元格的绝对坐标
+func (lyt *LayoutManager) CellOf(id ID) (cell Bound, ok bool) {
+	var elem *Element
+	if elem, ok = lyt.Element(id); ok {
+		x, y := lyt.origin(elem.parent)
+		cell = elem.cell
+		cell.X, cell.Y = cell.X+x, cell.Y+y
+	}
+	return
+}
+
+type gridLayout struct {}
+
+// 表格在 EndLayout 时统一摆放
+func (gridLayout) Measure(g *Group, elem *Element) {}
+
+func (gridLayout) Place(g *Group, elem *Element, c *Bound) {}
+
+func (gridLayout) Arrange(g *Group, children []*Element) {
+	cols := len(g.grid.columns)
+	if cols == 0 {
+		cols = 1
+	}
+	var (
+		rows = (len(children) + cols - 1) / cols
+		colContent = make([]float32, cols)
+		rowContent = make([]float32, rows)
+		availW, availH float32
+	)
+	// 行高取这一行所有单元格的最大值, 同一行的单元格(CellOf)高度相同,
+	// 这样行之间的分隔线是对齐的; 列宽同理
+	for i, c := range children {
+		dx, dy := g.Extent(c)
+		r, col := i/cols, i%cols
+		colContent[col] = math.Max(colContent[col], dx)
+		rowContent[r] = math.Max(rowContent[r], dy)
+	}
+	// 可用空间要去掉 padding 和单元格之间的间隔
+	if g.hasSize {
+		availW, availH = g.Content()
+		availW -= g.Spacing * float32(cols-1)
+		availH -= g.Spacing * float32(rows-1)
+	}
+
+	var (
+		colSize = resolveTracks(g.grid.columns, colContent, availW)
+		rowSize []float32
+	)
+	if g.grid.square {
+		rowSize = make([]float32, rows)
+		for i := range children {
+			r, col := i/cols, i%cols
+			rowSize[r] = math.Max(rowSize[r], colSize[col])
+		}
+	} else {
+		rowSize = resolveTracks(g.grid.rows, rowContent, availH)
+	}
+	var (
+		colX, w = offsets(colSize, g.Spacing)
+		rowY, h = offsets(rowSize, g.Spacing)
+	)
+	for i, c := range children {
+		r, col := i/cols, i%cols
+		c.cell = Bound{g.Padding.Left + colX[col], g.Padding.Top + rowY[r], colSize[col], rowSize[r]}
+		// 内容比单元格小时按 Gravity 对齐
+		dx, dy := g.Extent(c)
+		gravity := g.gravityOf(c)
+		c.X = c.cell.X + c.Left + math.Max(c.cell.W-dx, 0)*gravity.X
+		c.Y = c.cell.Y + c.Top + math.Max(c.cell.H-dy, 0)*gravity.Y
+		if g.grid.hasFit && c.measure != nil && !c.group {
+			c.fit, c.hasFit = g.grid.fit, true
+			c.Bound = c.cell
+			if w, h := c.measure(0, 0); g.grid.fit == FitContain {
+				c.Bound = FitRect(FitContain, w, h, c.cell)
+			}
+		}
+	}
+	g.Size.W, g.Size.H = w, h
+}
+
+// 固定大小的先计算, 然后是内容, 最后按比例分配剩余的空间
+// avail = 0 时不限制大小, 此时 TrackFraction 按内容计算
+func resolveTracks(tracks []TrackSize, content []float32, avail float32) []float32 {
+	var (
+		sizes = make([]float32, len(content))
+		used, fr float32
+	)
+	for i := range sizes {
+		t := trackAt(tracks, i)
+		switch t.Type {
+		case TrackFixed:
+			sizes[i] = t.Value
+		case TrackFraction:
+			if avail > 0 {
+				fr += t.Value
+				continue
+			}
+			sizes[i] = content[i]
+		default:
+			sizes[i] = content[i]
+		}
+		used += sizes[i]
+	}
+	if fr > 0 {
+		left := math.Max(avail-used, 0)
+		for i := range sizes {
+			if t := trackAt(tracks, i); t.Type == TrackFraction {
+				sizes[i] = left * t.Value / fr
+			}
+		}
+	}
+	return sizes
+}
+
+func trackAt(tracks []TrackSize, i int) TrackSize {
+	if i < len(tracks) {
+		return tracks[i]
+	}
+	return TrackSize{}
+}
+
+// 每个轨道的起始位置和总大小, 轨道之间间隔 gap
+func offsets(sizes []float32, gap float32) (off []float32, total float32) {
+	off = make([]float32, len(sizes))
+	for i, size := range sizes {
+		if i > 0 {
+			total += gap
+		}
+		off[i] = total
+		total += size
+	}
+	return
+}
@@ -0,0 +1,149 @@
+package gui
+
+import (
+	"testing"
+)
+
+func TestGridTrackSizes(t *testing.T) {
+	lyt := New()
+
+	box := func(w, h float32) MeasureFunc {
+		return func(maxW, maxH float32) (float32, float32) { return w, h }
+	}
+	lyt.SetMeasure(2, box(30, 10))
+	lyt.SetMeasure(3, box(60, 20))
+	lyt.SetMeasure(4, box(30, 10))
+
+	frame := func() {
+		lyt.Move(10, 10)
+		lyt.PushLayout(GridLayout, layoutOf(lyt, 1))
+		lyt.SetSize(200, 0)
+		lyt.SetColumnSizes([]TrackSize{Fixed(120), Fraction(1)})
+		for id := ID(2); id <= 4; id++ {
+			elem, _ := lyt.BeginElement(id)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	// spacing = 4, fraction column = 200 - 120 - 4
+	expect := map[ID]Bound{
+		2: {10, 10, 120, 20},
+		3: {134, 10, 76, 20},
+		4: {10, 34, 120, 10},
+	}
+	for id, b := range expect {
+		if cell, _ := lyt.CellOf(id); cell != b {
+			t.Error("cell of", id, ":", cell, "expected:", b)
+		}
+	}
+	if elem, _ := lyt.Element(3); elem.X != 124 || elem.Y != 0 {
+		t.Error("element in cell:", elem.Bound)
+	}
+	if g, _ := lyt.Element(1); g.W != 200 || g.H != 34 {
+		t.Error("grid size:", g.Bound)
+	}
+}
+
+func TestGridCellGravity(t *testing.T) {
+	lyt := New()
+
+	center := NewProperty()
+	center.GravityH, center.GravityV = .5, .5
+	center.Width, center.Height = 20, 10
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(GridLayout, layoutOf(lyt, 1))
+		lyt.SetColumnSizes([]TrackSize{Fixed(100)})
+		lyt.SetRowSizes([]TrackSize{Fixed(50)})
+		elem, _ := lyt.BeginElement(2)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	lyt.SetProperty(2, center)
+	frame()
+
+	if elem, _ := lyt.Element(2); elem.X != 40 || elem.Y != 20 {
+		t.Error("content should be centered in cell:", elem.Bound)
+	}
+	if cell, _ := lyt.CellOf(2); cell != (Bound{0, 0, 100, 50}) {
+		t.Error("cell:", cell)
+	}
+}
+
+func TestGridRowSync(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(GridLayout, layoutOf(lyt, 1))
+		lyt.SetColumnSizes([]TrackSize{Auto(), Auto()})
+		// short left cell, tall right cell
+		for id, h := range []float32{10, 40} {
+			lyt.Cursor.SetSize(30, h).To(ID(2 + id))
+			elem, _ := lyt.BeginElement(ID(2 + id))
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	left, _ := lyt.CellOf(2)
+	right, _ := lyt.CellOf(3)
+	if left.H != 40 || right.H != 40 || left.Y != right.Y {
+		t.Error("cells in a row should share the row height:", left, right)
+	}
+}
+
+func TestSquareCells(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(GridLayout, layoutOf(lyt, 1))
+		lyt.SetSize(308, 0)
+		lyt.SetColumnSizes([]TrackSize{Fraction(1), Fraction(1), Fraction(1)})
+		lyt.SetSquareCells(true)
+		for id := ID(2); id <= 7; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(10, 10)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	// (308 - 2*4) / 3
+	for id := ID(2); id <= 7; id++ {
+		if cell, _ := lyt.CellOf(id); cell.W != 100 || cell.H != 100 {
+			t.Error("cell", id, "should be square:", cell)
+		}
+	}
+	if g, _ := lyt.Element(1); g.H != 204 {
+		t.Error("grid height:", g.H)
+	}
+}
+
+func TestAutoColumns(t *testing.T) {
+	cases := []struct {
+		width float32
+		cols  int
+	}{
+		{50, 1},   // narrower than one cell
+		{200, 2},  // exact fit
+		{250, 3},  // 83 is closer to 100 than 125
+		{230, 2},  // 115 is closer than 76
+		{1000, 10},
+	}
+	for _, c := range cases {
+		if n := AutoColumns(100, c.width); n != c.cols {
+			t.Error("columns for width", c.width, "=", n, "expected:", c.cols)
+		}
+	}
+}
@@ -74,6 +74,9 @@ const (
 	LinearVertical LayoutType = iota
 	LinearHorizontal
 	LinearOverLay
+	GridLayout
+	FrameLayout
+	FlowLayout
 )
 
 var layout bool
@@ -178,6 +181,7 @@ func EndVertical() {
 }
 
 // 参数需要一个 Rect，暂时用 Cursor 代替
+// 子元素用 Layout.Dock/DockFill 停靠在四周和中心
 func BeginDock(id ID, w, h float32) {
 	gContext.BeginLayout(id, LinearOverLay)
 	gContext.Layout.SetSize(w, h)
@@ -187,6 +191,15 @@ func EndDock() {
 	gContext.EndLayout()
 }
 
+// 表格布局, 用 SetColumnSizes/SetRowSizes 设置行列
+func BeginGrid(id ID) {
+	gContext.BeginLayout(id, GridLayout)
+}
+
+func EndGrid() {
+	gContext.EndLayout()
+}
+
 // Theme:
 func UseTheme(style *Style) {
 	gContext.UseTheme(style)
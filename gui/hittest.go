@@ -0,0 +1,135 @@
+package gui
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// 找出 p 所在的最上层元素(后声明的元素在上层)
+// 禁用的元素会被跳过, 点击落到它下面的元素上
+// 被裁剪掉的部分也不会响应点击
+// 有多个根布局(Root)时, 从最上层的根布局开始查找
+func (lyt *LayoutManager) HitTest(p mgl32.Vec2) (id ID, ok bool) {
+	id, _, ok = lyt.hit(p)
+	return
+}
+
+// 同 HitTest, 并且返回点击位置在元素中的比例(0~1), 比如滑动条的位置
+func (lyt *LayoutManager) HitTestFraction(p mgl32.Vec2) (id ID, fx, fy float32, ok bool) {
+	var b Bound
+	if id, b, ok = lyt.hit(p); ok {
+		if b.W > 0 {
+			fx = (p[0] - b.X) / b.W
+		}
+		if b.H > 0 {
+			fy = (p[1] - b.Y) / b.H
+		}
+	}
+	return
+}
+
+// 返回点中的元素和它的绝对坐标
+func (lyt *LayoutManager) hit(p mgl32.Vec2) (id ID, b Bound, ok bool) {
+	if len(lyt.layers) < 2 {
+		return lyt.hitLayer(p)
+	}
+	lyt.saveLayer()
+	for i := len(lyt.layers) - 1; i >= 0 && !ok; i-- {
+		lyt.loadLayer(i)
+		id, b, ok = lyt.hitLayer(p)
+	}
+	lyt.loadLayer(lyt.active)
+	return
+}
+
+func (lyt *LayoutManager) hitLayer(p mgl32.Vec2) (id ID, b Bound, ok bool) {
+	for i := len(lyt.uiElements) - 1; i > 0; i-- {
+		elem := &lyt.uiElements[i]
+		if elem.disabled {
+			continue
+		}
+		if elem.clipped && !elem.clip.InRange(p) {
+			continue
+		}
+		if b = lyt.absBound(elem); b.InRange(p) && !inHole(elem, b, p) {
+			return elem.id, b, true
+		}
+	}
+	return -1, Bound{}, false
+}
+
+// 在元素上挖一个不响应点击的洞(相对于元素的左上角), 点击会落到下面的元素上,
+// 比如面板上透出场景的区域. 洞会一直保留(见 ClearHoles), 重复添加同一个区域只算一次
+func (lyt *LayoutManager) AddHole(id ID, rect Bound) {
+	elem := lyt.obtain(id)
+	for _, h := range elem.holes {
+		if h == rect {
+			return
+		}
+	}
+	elem.holes = append(elem.holes, rect)
+}
+
+// 清除元素所有的洞
+func (lyt *LayoutManager) ClearHoles(id ID) {
+	if elem, ok := lyt.Element(id); ok {
+		elem.holes = elem.holes[:0]
+	}
+}
+
+// p 是否落在元素的某个洞里, b 是元素的绝对坐标
+func inHole(elem *Element, b Bound, p mgl32.Vec2) bool {
+	for _, h := range elem.holes {
+		h.X, h.Y = h.X+b.X, h.Y+b.Y
+		if h.InRange(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// 框选: 找出可见部分(裁剪之后)和 rect(绝对坐标)相交的元素, 按声明的顺序, 不包括 Group
+func (lyt *LayoutManager) SelectIn(rect Bound) (ids []ID) {
+	for i := 1; i < len(lyt.uiElements); i++ {
+		elem := &lyt.uiElements[i]
+		if elem.group {
+			continue
+		}
+		b := lyt.absBound(elem)
+		if elem.clipped {
+			b = b.Intersect(elem.clip)
+		}
+		if !b.Intersect(rect).Empty() {
+			ids = append(ids, elem.id)
+		}
+	}
+	return
+}
+
+// 找出非重叠布局中互相重叠的兄弟元素, 调试用
+// LinearOverLay 和 FrameLayout 本来就是叠放的, 不检查
+// 只共享一条边的元素不算重叠
+func (lyt *LayoutManager) Overlaps() (pairs [][2]ID) {
+	siblings := make(map[ID][]int)
+	for i := 1; i < len(lyt.uiElements); i++ {
+		parent := lyt.uiElements[i].parent
+		siblings[parent] = append(siblings[parent], i)
+	}
+	for i := 1; i < len(lyt.uiElements); i++ {
+		g := &lyt.uiElements[i]
+		if !g.group || g.layout == LinearOverLay || g.layout == FrameLayout {
+			continue
+		}
+		children := siblings[g.id]
+		for a := 0; a < len(children); a++ {
+			ea := &lyt.uiElements[children[a]]
+			ba := lyt.absBound(ea)
+			for b := a + 1; b < len(children); b++ {
+				eb := &lyt.uiElements[children[b]]
+				if !ba.Intersect(lyt.absBound(eb)).Empty() {
+					pairs = append(pairs, [2]ID{ea.id, eb.id})
+				}
+			}
+		}
+	}
+	return
+}
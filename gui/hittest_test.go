@@ -0,0 +1,162 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestHitTestDisabled(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearOverLay, layoutOf(lyt, 1))
+		// 2 is below 3
+		for id := ID(2); id <= 3; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(50, 50)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+
+	disabled := NewProperty()
+	disabled.Enabled = false
+	lyt.SetProperty(3, disabled)
+	frame()
+
+	if id, ok := lyt.HitTest(mgl32.Vec2{25, 25}); !ok || id != 2 {
+		t.Error("click should fall through the disabled element, hit:", id)
+	}
+
+	visited := map[ID]bool{}
+	lyt.Visit(func(info ElementInfo) {
+		visited[info.ID] = info.Enabled
+	})
+	if enabled, ok := visited[3]; !ok || enabled {
+		t.Error("disabled element should be visited as disabled")
+	}
+
+	// NewProperty 创建的属性是启用的
+	lyt.SetProperty(3, NewProperty())
+	frame()
+	if id, ok := lyt.HitTest(mgl32.Vec2{25, 25}); !ok || id != 3 {
+		t.Error("default property should keep the element enabled, hit:", id)
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+		// pulled back onto 2 by a negative margin
+		lyt.Cursor.SetMargin(0, -5, 0, 0).To(3)
+		elem, _ = lyt.BeginElement(3)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+		elem, _ = lyt.BeginElement(4)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	pairs := lyt.Overlaps()
+	if len(pairs) != 1 || pairs[0] != [2]ID{2, 3} {
+		t.Error("overlapping pairs:", pairs)
+	}
+}
+
+func TestHitTestFraction(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(10, 20)
+		lyt.Cursor.SetSize(100, 40).To(2)
+		elem, _ := lyt.BeginElement(2)
+		lyt.EndElement(elem)
+	}
+	frame()
+	frame()
+
+	id, fx, fy, ok := lyt.HitTestFraction(mgl32.Vec2{60, 40})
+	if !ok || id != 2 || fx != .5 || fy != .5 {
+		t.Error("hit fraction:", id, fx, fy, ok)
+	}
+	if id, fx, _, _ := lyt.HitTestFraction(mgl32.Vec2{35, 40}); id != 2 || fx != .25 {
+		t.Error("hit fraction:", id, fx)
+	}
+}
+
+func TestHitTestHole(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearOverLay, layoutOf(lyt, 1))
+		// 2 is behind the panel 3
+		for id := ID(2); id <= 3; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(100, 100)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+	lyt.AddHole(3, Bound{20, 20, 30, 30})
+	lyt.AddHole(3, Bound{20, 20, 30, 30})
+	frame()
+
+	if id, ok := lyt.HitTest(mgl32.Vec2{30, 30}); !ok || id != 2 {
+		t.Error("click in the hole should reach the element behind, hit:", id)
+	}
+	if id, ok := lyt.HitTest(mgl32.Vec2{10, 10}); !ok || id != 3 {
+		t.Error("click outside the hole should hit the panel, hit:", id)
+	}
+	if e, _ := lyt.Element(3); len(e.holes) != 1 {
+		t.Error("the same hole should be added once:", e.holes)
+	}
+
+	lyt.ClearHoles(3)
+	if id, _ := lyt.HitTest(mgl32.Vec2{30, 30}); id != 3 {
+		t.Error("cleared hole should hit the panel, hit:", id)
+	}
+}
+
+func TestSelectIn(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearOverLay, layoutOf(lyt, 1))
+		for i, p := range [][2]float32{{0, 0}, {40, 40}, {100, 10}, {10, 90}, {60, 0}} {
+			lyt.Move(p[0], p[1])
+			if i == 4 {
+				// clipped away from the selection
+				lyt.PushClip(Bound{70, 0, 20, 20})
+			}
+			elem, _ := lyt.BeginElement(ID(2 + i))
+			elem.Size(20, 20)
+			lyt.EndElement(elem)
+		}
+		lyt.PopClip()
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	ids := lyt.SelectIn(Bound{10, 10, 55, 45})
+	if len(ids) != 2 || ids[0] != 2 || ids[1] != 3 {
+		t.Error("unexpected selection:", ids)
+	}
+}
@@ -0,0 +1,51 @@
+package gui
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// 悬停的元素变化时产生的事件
+type HoverEvent struct {
+	ID      ID
+	Entered bool
+}
+
+type hoverState struct {
+	id     ID
+	ok     bool
+	frames int
+	events []HoverEvent
+}
+
+// 鼠标所在的元素, 和 HitTest 的规则一样, 每一帧调用一次
+// 和上一次调用相比, 悬停的元素变化时产生离开/进入事件, 见 HoverEvents
+func (lyt *LayoutManager) Hovered(p mgl32.Vec2) (id ID, ok bool) {
+	h := &lyt.hover
+	h.events = h.events[:0]
+
+	id, ok = lyt.HitTest(p)
+	if ok == h.ok && id == h.id {
+		if ok {
+			h.frames++
+		}
+		return
+	}
+	if h.ok {
+		h.events = append(h.events, HoverEvent{ID: h.id, Entered: false})
+	}
+	if ok {
+		h.events = append(h.events, HoverEvent{ID: id, Entered: true})
+	}
+	h.id, h.ok, h.frames = id, ok, 0
+	return
+}
+
+// 最近一次 Hovered 产生的事件, 离开事件在进入事件之前
+func (lyt *LayoutManager) HoverEvents() []HoverEvent {
+	return lyt.hover.events
+}
+
+// 当前元素已经悬停的帧数(进入的那一帧为 0), 可以用来延迟显示 tooltip
+func (lyt *LayoutManager) HoverFrames() int {
+	return lyt.hover.frames
+}
@@ -0,0 +1,50 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestHoverEvents(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		for id := ID(2); id <= 3; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(20, 20)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	steps := []struct {
+		p      mgl32.Vec2
+		events []HoverEvent
+	}{
+		{mgl32.Vec2{10, 10}, []HoverEvent{{2, true}}},
+		{mgl32.Vec2{12, 10}, nil},
+		{mgl32.Vec2{35, 10}, []HoverEvent{{2, false}, {3, true}}},
+		{mgl32.Vec2{100, 100}, []HoverEvent{{3, false}}},
+	}
+	for i, step := range steps {
+		lyt.Hovered(step.p)
+		events := lyt.HoverEvents()
+		if len(events) != len(step.events) {
+			t.Error("step", i, "events:", events, "expected:", step.events)
+			continue
+		}
+		for j := range events {
+			if events[j] != step.events[j] {
+				t.Error("step", i, "events:", events, "expected:", step.events)
+			}
+		}
+		if i == 1 && lyt.HoverFrames() != 1 {
+			t.Error("hover frames:", lyt.HoverFrames())
+		}
+	}
+}
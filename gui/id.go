@@ -9,3 +9,16 @@ func GenID(name string) ID {
 }
 
 type IdMap map[string]ID
+
+// AutoElement 分配的 Id 从这里开始, 手动传入的 Id 应该小于它
+const AutoIDBase ID = 1 << 24
+
+// 自动分配一个 Id 并开始布局元素, 和 BeginElement 一样需要调用 EndElement
+// Id 在每一帧(BeginFrame)从 AutoIDBase 开始依次递增, 所以只有每一帧调用的顺序不变时,
+// 同一个元素在不同帧的 Id 才相同. 顺序会变化的元素(比如可以排序的列表)应该手动指定 Id
+func (lyt *LayoutManager) AutoElement() (id ID, elem *Element, ok bool) {
+	id = AutoIDBase + lyt.autoSeq
+	lyt.autoSeq++
+	elem, ok = lyt.BeginElement(id)
+	return
+}
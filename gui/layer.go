@@ -0,0 +1,85 @@
+package gui
+
+// 根布局(层), 每一层有自己的元素和 Group 栈, 互相独立布局
+type layer struct {
+	name       string
+	uiElements []Element
+	ids        map[ID]int
+	groupStack []Group
+	hGroup     *Group
+	cursor     cursor
+	// 上一帧的元素和绝对坐标, 见 BeginFrame
+	prev       []Element
+	prevIndex  map[ID]int
+	prevBounds map[ID]Bound
+}
+
+// 切换当前的根布局, 不存在时创建一个新的, 默认的根布局名字为 ""
+// 根布局按创建的顺序叠放, 后创建的在上层, HitTest 从上层开始查找
+//
+//	lyt.Root("hud")
+//	... world-space HUD
+//	lyt.Root("menu")
+//	... screen-space menu
+func (lyt *LayoutManager) Root(name string) {
+	if len(lyt.layers) == 0 {
+		lyt.layers = append(lyt.layers, layer{name: ""})
+		lyt.active = 0
+	}
+	lyt.saveLayer()
+	for i := range lyt.layers {
+		if lyt.layers[i].name == name {
+			lyt.loadLayer(i)
+			lyt.active = i
+			return
+		}
+	}
+	lyt.layers = append(lyt.layers, layer{name: name})
+	lyt.active = len(lyt.layers) - 1
+	lyt.uiElements = make([]Element, 0, 32)
+	lyt.ids = make(map[ID]int)
+	lyt.groupStack = make([]Group, 0, 8)
+	lyt.Cursor = cursor{}
+	lyt.prev, lyt.prevIndex, lyt.prevBounds = nil, nil, nil
+	lyt.pushRoot(lyt.NewElement(0))
+}
+
+// 当前根布局的名字
+func (lyt *LayoutManager) RootName() string {
+	if len(lyt.layers) == 0 {
+		return ""
+	}
+	return lyt.layers[lyt.active].name
+}
+
+func (lyt *LayoutManager) saveLayer() {
+	l := &lyt.layers[lyt.active]
+	l.uiElements, l.groupStack, l.hGroup, l.cursor = lyt.uiElements, lyt.groupStack, lyt.hGroup, lyt.Cursor
+	l.ids = lyt.ids
+	l.prev, l.prevIndex, l.prevBounds = lyt.prev, lyt.prevIndex, lyt.prevBounds
+}
+
+func (lyt *LayoutManager) loadLayer(i int) {
+	l := &lyt.layers[i]
+	lyt.uiElements, lyt.groupStack, lyt.hGroup, lyt.Cursor = l.uiElements, l.groupStack, l.hGroup, l.cursor
+	lyt.ids = l.ids
+	lyt.prev, lyt.prevIndex, lyt.prevBounds = l.prev, l.prevIndex, l.prevBounds
+}
+
+// 依次切换到每一个根布局执行 fn, 最后恢复当前的根布局
+func (lyt *LayoutManager) eachLayer(fn func()) {
+	if len(lyt.layers) < 2 {
+		fn()
+		return
+	}
+	active := lyt.active
+	lyt.saveLayer()
+	for i := range lyt.layers {
+		lyt.active = i
+		lyt.loadLayer(i)
+		fn()
+		lyt.saveLayer()
+	}
+	lyt.active = active
+	lyt.loadLayer(active)
+}
@@ -0,0 +1,85 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestRootLayers(t *testing.T) {
+	lyt := New()
+
+	box := func(id ID) {
+		lyt.Move(0, 0)
+		lyt.Cursor.SetSize(20, 20).To(id)
+		elem, _ := lyt.BeginElement(id)
+		lyt.EndElement(elem)
+	}
+	frame := func() {
+		lyt.Root("hud")
+		box(2)
+		lyt.Root("menu")
+		box(3)
+		lyt.Root("")
+	}
+	frame()
+	frame()
+
+	if id, ok := lyt.HitTest(mgl32.Vec2{10, 10}); !ok || id != 3 {
+		t.Error("top root should win:", id, ok)
+	}
+	if lyt.RootName() != "" {
+		t.Error("active root should be restored:", lyt.RootName())
+	}
+
+	// each root keeps its own elements
+	if _, ok := lyt.Element(2); ok {
+		t.Error("default root should not see hud elements")
+	}
+	lyt.Root("hud")
+	if _, ok := lyt.Element(3); ok {
+		t.Error("hud should not see menu elements")
+	}
+	if elem, ok := lyt.Element(2); !ok || elem.W != 20 {
+		t.Error("hud element:", elem, ok)
+	}
+}
+
+func TestLayerFrames(t *testing.T) {
+	lyt := New()
+
+	box := func(id ID, x float32) {
+		lyt.Move(x, 0)
+		lyt.Cursor.SetSize(20, 20).To(id)
+		elem, _ := lyt.BeginElement(id)
+		lyt.EndElement(elem)
+	}
+	frame := func(hud ...ID) {
+		lyt.BeginFrame()
+		lyt.Root("hud")
+		for _, id := range hud {
+			box(id, 0)
+		}
+		lyt.Root("menu")
+		box(2, 50)
+		lyt.Root("")
+		lyt.EndFrame()
+	}
+	frame(2, 3)
+	frame(2, 3)
+
+	// 背景层不再声明 3
+	frame(2)
+	lyt.Root("hud")
+	if _, ok := lyt.Element(3); ok {
+		t.Error("element dropped from a background root should be removed")
+	}
+	if b, ok := lyt.PrevBound(2); !ok || b.X != 0 {
+		t.Error("prev bound should come from the same root:", b, ok)
+	}
+	lyt.Root("menu")
+	if b, ok := lyt.PrevBound(2); !ok || b.X != 50 {
+		t.Error("prev bound should come from the same root:", b, ok)
+	}
+	lyt.Root("")
+}
@@ -37,6 +37,83 @@ type Element struct{
 	Bound
 	// Margin
 	Margin
+
+	// 所属的 Group
+	parent ID
+	// Group 的 (x, y) 是绝对坐标
+	group bool
+	layout LayoutType
+	// Group 内容的滚动偏移
+	scroll mgl32.Vec2
+
+	// 测量内容的大小, 见 SetMeasure
+	measure MeasureFunc
+	// 内容被截断(宽度小于内容的宽度)
+	truncated bool
+	// 所在的单元格(GridLayout), 相对于所属的 Group
+	cell Bound
+	// 线性布局中的排序值, 见 SetSortKey
+	sortKey int
+	// 背景向外扩展的距离, 不影响布局
+	inset Margin
+	// Group 内容的大小(包括 padding), 用来计算滚动范围
+	content Bound
+	// 元素自己的 Gravity, 覆盖 Group 的 Gravity
+	gravity *Gravity
+	disabled bool
+	// 声明时的裁剪区域(绝对坐标)和它的圆角, 见 PushClipRadius
+	clip Bound
+	clipRadius float32
+	clipped bool
+	// 裁剪区域所属的 Group, 滚动时重新计算, 见 shiftClips
+	clipOwner ID
+	clipLocal Bound
+	// 基线到元素顶部的距离, 见 SetBaseline
+	baseline float32
+	hasBaseline bool
+	// 元素自己的不透明度, 没有设置时为 1
+	opacity float32
+	hasOpacity bool
+	// 摆放完成后裁剪到父容器的内容区域, 见 Property.ClampToParent
+	clampToParent bool
+	// 本帧通过 Cursor 设置了大小, 不再使用测量的大小
+	sized bool
+	// 内容的缩放方式, 见 SetFit
+	fit FitMode
+	hasFit bool
+	// 相对于根布局的大小(百分比), 见 Property.WidthVW
+	vw, vh float32
+	// 分配剩余空间的权重, 见 Property.Weight
+	weight float32
+	// 相对于父容器某条边的位置, 覆盖 Gravity, 见 cursor.Anchor
+	anchorH, anchorV anchor
+	// 宽和高互相依赖(比如折行的文字), 见 SetCoupled
+	coupled bool
+	// 内容裁剪到元素自己的范围, 见 Property.Clip
+	selfClip bool
+	// 只能沿一个方向滚动, 见 SetScrollAxis
+	scrollAxis Axis
+	scrollLocked bool
+	// 按比例填充父容器, 见 SetAspectRatio
+	aspect aspectFill
+	// 通过 SetProperty 设置了非零的 Margin, 不使用 Group 的 SetChildMargin
+	ownMargin bool
+	// 不响应点击的区域(相对于元素), 见 AddHole
+	holes []Bound
+	// Group 裁剪到自己的范围(Property.Clip)时的圆角半径, 只由 SetCornerRadius 设置
+	radius float32
+	// Group 的子元素在 uiElements 中的索引(排列之后的顺序), EndLayout 时记录, 见 VisitTree
+	kids []int
+	// Group 通过 SetSize 固定了大小的方向, 见 RelayoutGroup
+	fixedW, fixedH bool
+	// 线性布局中和前一个元素之间多出来的空白, 重新排列时保留, 见 measureLead
+	lead float32
+	// 收缩的权重和最小大小, 没有设置时权重为 1, 见 Property.ShrinkWeight
+	shrink float32
+	hasShrink bool
+	minW, minH float32
+	// 最后一次在哪个 Group 中声明(Group.serial), 用来检查重复的 ID 和 MatchSize
+	declaredIn int
 }
 
 type Property struct {
@@ -53,6 +130,36 @@ type Property struct {
 	// Element Size
 	Width float32
 	Height float32
+
+	// 禁用的元素不响应点击, 但是仍然占据空间和绘制
+	Enabled bool
+
+	// 不透明度, 1 为不透明(默认), 0 为完全透明. 会乘上所有上层 Group 的不透明度,
+	// 见 ElementInfo.Opacity. 不影响布局
+	Opacity float32
+
+	// 超出父容器内容区域(去掉 padding)的部分被裁掉, 比如进度条的填充部分
+	ClampToParent bool
+
+	// 相对于根布局(视口)的大小, 单位是百分比, 和嵌套的层数无关, 0 表示不使用
+	// 设置后覆盖 Width/Height
+	WidthVW, HeightVH float32
+
+	// 线性布局有剩余空间时, 按权重分配给元素(主轴方向)
+	Weight float32
+
+	// 元素的内容裁剪到元素自己的范围(比如图片), 不需要再包一层 Group
+	Clip bool
+
+	// SetShrinkToFit 的 Group 中子元素超出 Group 时, 按权重收缩(主轴方向),
+	// 0 表示不收缩, 最多收缩到 MinWidth/MinHeight. NewProperty 中默认为 1
+	ShrinkWeight float32
+	MinWidth, MinHeight float32
+}
+
+// 默认属性, Property 应该从这里创建, 否则 Enabled 为 false, Opacity 为 0
+func NewProperty() Property {
+	return Property{Enabled: true, Opacity: 1, ShrinkWeight: 1}
 }
 
 // UI绘制边界
@@ -75,6 +182,7 @@ const (
 	FlagSize DirtyFlag = 1 << iota
 	FlagMargin
 	FlagGravity
+	FlagAnchor
 )
 
 // Shadow of current ui-element
@@ -82,6 +190,8 @@ type cursor struct {
 	Bound
 	Margin
 	Gravity Gravity
+	// 水平和垂直方向的锚点, 见 Anchor
+	anchorH, anchorV anchor
 	owner ID
 	Flag DirtyFlag // dirty flag
 }
@@ -91,23 +201,73 @@ func (c *cursor) Reset()  {
 }
 
 // set flag
+// 参数顺序和 SetPadding 一样是 (top, left, right, bottom), 和 Margin 的字段顺序不同
 func (c *cursor) SetMargin(top, left, right, bottom float32) *cursor{
 	c.Flag |= FlagMargin
-	c.Margin = Margin{top, left, bottom, right}
+	c.Margin = Margin{Top: top, Left: left, Right: right, Bottom: bottom}
+	return c
+}
+
+// 只设置一边的 Margin, 其它边不变
+func (c *cursor) MarginTop(v float32) *cursor {
+	c.Flag |= FlagMargin
+	c.Margin.Top = v
+	return c
+}
+
+func (c *cursor) MarginLeft(v float32) *cursor {
+	c.Flag |= FlagMargin
+	c.Margin.Left = v
+	return c
+}
+
+func (c *cursor) MarginRight(v float32) *cursor {
+	c.Flag |= FlagMargin
+	c.Margin.Right = v
+	return c
+}
+
+func (c *cursor) MarginBottom(v float32) *cursor {
+	c.Flag |= FlagMargin
+	c.Margin.Bottom = v
+	return c
+}
+
+// h 是左右两边, v 是上下两边
+func (c *cursor) MarginXY(h, v float32) *cursor {
+	c.Flag |= FlagMargin
+	c.Margin = Margin{Top: v, Left: h, Right: h, Bottom: v}
 	return c
 }
 
 func (c *cursor) SetSize(w, h float32) *cursor{
 	c.Flag |= FlagSize
-	c.Bound.W = w
-	c.Bound.H = h
+	c.Bound.W = finite(w)
+	c.Bound.H = finite(h)
 	return c
 }
 
 func (c *cursor) SetGravity(x, y float32) *cursor{
 	c.Flag |= FlagGravity
-	c.Gravity.X = x
-	c.Gravity.Y = y
+	c.Gravity.X = finite(x)
+	c.Gravity.Y = finite(y)
+	return c
+}
+
+// 只应用 mask 中标记的字段, 没有标记的字段(即使是零值)不会覆盖光标上已有的设置
+//	FlagSize:    Width, Height
+//	FlagMargin:  MarginTop, MarginLeft, MarginRight, MarginBottom
+//	FlagGravity: GravityH, GravityV
+func (c *cursor) ApplyProperty(p Property, mask DirtyFlag) *cursor {
+	if mask&FlagSize != 0 {
+		c.SetSize(p.Width, p.Height)
+	}
+	if mask&FlagMargin != 0 {
+		c.SetMargin(p.MarginTop, p.MarginLeft, p.MarginRight, p.MarginBottom)
+	}
+	if mask&FlagGravity != 0 {
+		c.SetGravity(p.GravityH, p.GravityV)
+	}
 	return c
 }
 
@@ -138,12 +298,36 @@ func (b *Bound) InRange(p mgl32.Vec2) bool{
 	return true
 }
 
+// 两个矩形的交集, 没有交集时 W/H 为 0
+func (b Bound) Intersect(o Bound) Bound {
+	var (
+		x0, y0 = math.Max(b.X, o.X), math.Max(b.Y, o.Y)
+		x1, y1 = math.Min(b.X+b.W, o.X+o.W), math.Min(b.Y+b.H, o.Y+o.H)
+	)
+	return Bound{x0, y0, math.Max(x1-x0, 0), math.Max(y1-y0, 0)}
+}
+
+// 包含两个矩形的最小矩形
+func (b Bound) Union(o Bound) Bound {
+	var (
+		x0, y0 = math.Min(b.X, o.X), math.Min(b.Y, o.Y)
+		x1, y1 = math.Max(b.X+b.W, o.X+o.W), math.Max(b.Y+b.H, o.Y+o.H)
+	)
+	return Bound{x0, y0, x1-x0, y1-y0}
+}
+
+func (b Bound) Empty() bool {
+	return b.W <= 0 || b.H <= 0
+}
+
 type LayoutManager struct {
 	Horizontal, Vertical Direction
 	Cursor               cursor
 	Align
 	// ui bound 是一直存储的，记录一些持久化的数据
 	uiElements           []Element // element uiElements
+	// id -> uiElements 中的索引, 见 index
+	ids                  map[ID]int
 
 	// group 是 fifo 的结构,记录动态的数据
 	groupStack           []Group // groupStack uiElements
@@ -153,44 +337,212 @@ type LayoutManager struct {
 
 	// default ui-element spacing
 	spacing float32
+
+	// 缓存的布局结果, 见 BeginCached
+	cache layoutCache
+
+	// 正在进行的滚动动画
+	scrolling map[ID]*scrollAnim
+	// 滚动条滑块的最小长度, 见 SetMinThumbSize
+	minThumb float32
+
+	// clip stack, 每一层都是和上一层的交集
+	clips []clipRect
+
+	// 设备像素/布局单位, 0 按 1 处理
+	scale float32
+	// 输出时对齐到设备像素
+	pixelSnap bool
+	// 输出坐标的小数位数, 见 SetExportPrecision
+	precision int
+	hasPrecision bool
+
+	// 下一个元素的大小匹配, 见 MatchSize
+	match sizeMatch
+
+	// 所有的根布局, 按创建的顺序从下到上, 见 Root
+	layers []layer
+	active int
+
+	// 位置和大小对齐的网格大小, 见 SetGrid
+	gridCell float32
+	// 元素大小的取整方式, 见 SetRoundMode
+	roundMode RoundMode
+
+	// 根布局(视口)的大小, 见 SetRootSize
+	rootSize struct{W, H float32}
+	hasRootSize bool
+	// 根布局的大小变化之后的第一帧不记录上一帧的坐标
+	rootChanged bool
+
+	// 上一次 solveCoupled 迭代的次数和是否收敛
+	coupledPasses int
+	coupledConverged bool
+
+	// 检查布局的不变量, 见 SetDebug
+	debug bool
+
+	// 正在拖动的元素, 见 BeginDrag
+	drag dragState
+
+	// WrapContent 元素没有测量结果时的大小, 见 SetDefaultContentSize
+	defaultContent struct{W, H float32}
+
+	// 保留模式下声明布局的函数, 见 Declare
+	tree func()
+
+	// Group 的编号, 见 Group.serial
+	groupSerial int
+	// 重复声明的元素返回这个元素, 对它的修改都会被丢弃
+	discard Element
+	// 重复的 ID 只打印一次日志
+	dupLogged bool
+	// 使用 BeginFrame, 见 rootPass
+	framed bool
+
+	// 下一个自动分配的 Id, 见 AutoElement
+	autoSeq ID
+
+	// BatchUpdate 中等待重新布局的 Group
+	batching bool
+	pending []pendingRelayout
+	// 每个 Group 最后一次 RelayoutGroup 的内容函数, Set* 标记的 Group 用它重新布局
+	contents map[ID]func()
+
+	// 鼠标悬停的状态, 见 Hovered
+	hover hoverState
+	// 焦点, 见 SetFocus
+	focus focusState
+
+	// 冻结时 BeginFrame 不再重新布局, 见 SetFrozen
+	frozen bool
+
+	// 窗口大小的变化, 见 OnResize
+	resize resizeState
+
+	// 当前根布局上一帧的元素和绝对坐标, 见 BeginFrame
+	prev []Element
+	// id -> prev 中的索引, 见 revive
+	prevIndex map[ID]int
+	prevBounds map[ID]Bound
+}
+
+// 创建一个可以直接使用的 LayoutManager
+// 布局只依赖 mgl32 的数学类型, 不需要渲染器(GL), 可以在服务端或者测试中使用
+func New() *LayoutManager {
+	lyt := &LayoutManager{}
+	lyt.Initialize()
+	return lyt
 }
 
 func (lyt *LayoutManager) Initialize() {
 	// init size, todo resize 会导致指针错误
 	lyt.uiElements = make([]Element, 0, 32)
+	lyt.ids = make(map[ID]int)
 	lyt.groupStack = make([]Group, 0, 8)
 	lyt.spacing = 4
 
 	// Create a default layout
-	bb := lyt.NewElement(0)
+	lyt.pushRoot(lyt.NewElement(0))
+}
+
+func (lyt *LayoutManager) pushRoot(bb *Element) {
+	bb.parent, bb.group, bb.layout = -1, true, LinearOverLay
 	ii := len(lyt.groupStack)
-	lyt.groupStack = append(lyt.groupStack, Group{LayoutType:LinearOverLay, Element: bb})
+	lyt.groupStack = append(lyt.groupStack, Group{LayoutType:LinearOverLay, Element: bb, Spacing: lyt.spacing})
 	lyt.hGroup = &lyt.groupStack[ii]
+	lyt.groupSerial++
+	lyt.hGroup.serial = lyt.groupSerial
+	if lyt.hasRootSize {
+		bb.W, bb.H = lyt.rootSize.W, lyt.rootSize.H
+		lyt.hGroup.hasSize = true
+	}
 }
 
 // 创建新的Layout
 func (lyt *LayoutManager) NewElement(id ID) *Element {
 	ii := len(lyt.uiElements)
 	lyt.uiElements = append(lyt.uiElements, Element{id:id})
+	if lyt.ids == nil {
+		lyt.ids = make(map[ID]int)
+	}
+	lyt.ids[id] = ii
 	return &lyt.uiElements[ii]
 }
 
 // 找出前一帧保存的大小
+// 内部使用: 返回的指针指向 uiElements, 创建新元素时 uiElements 扩容会让它失效,
+// 在 gui 包外应该使用 BoundOf
 func (lyt *LayoutManager) Element(id ID) (bb *Element, ok bool) {
-	if size := len(lyt.uiElements); size > int(id) {
-		if bb = &lyt.uiElements[id]; bb.id == id {
-			ok = true; return
+	if ii := lyt.index(id); ii >= 0 {
+		bb, ok = &lyt.uiElements[ii], true
+	}
+	return
+}
+
+// 找出元素, 如果不存在就创建一个
+// 用来在元素布局之前设置它的属性
+func (lyt *LayoutManager) obtain(id ID) *Element {
+	if ii := lyt.index(id); ii >= 0 {
+		elem := &lyt.uiElements[ii]
+		// BatchUpdate 中修改已经布局过的元素, 只标记它所在的 Group
+		if lyt.batching && elem.parent != id {
+			lyt.markRelayout(elem.parent, nil)
 		}
+		return elem
 	}
-	// Linear Search
-	for i := range lyt.uiElements {
-		if bb = &lyt.uiElements[i]; bb.id == id {
-			ok = true; break
+	if elem, ok := lyt.revive(id); ok {
+		return elem
+	}
+	return lyt.NewElement(id)
+}
+
+// 设置元素的属性, 在元素布局之前调用
+func (lyt *LayoutManager) SetProperty(id ID, p Property) {
+	elem := lyt.obtain(id)
+	elem.Margin = Margin{Top: p.MarginTop, Left: p.MarginLeft, Bottom: p.MarginBottom, Right: p.MarginRight}
+	// 没有设置 Margin 时仍然使用 Group 的 SetChildMargin
+	elem.ownMargin = elem.Margin != Margin{}
+	elem.gravity = &Gravity{p.GravityH, p.GravityV}
+	elem.W, elem.H = p.Width, p.Height
+	elem.disabled = !p.Enabled
+	elem.opacity = math.F32Clamp(finite(p.Opacity), 0, 1)
+	elem.hasOpacity = elem.opacity != 1
+	elem.clampToParent = p.ClampToParent
+	elem.vw, elem.vh = p.WidthVW, p.HeightVH
+	elem.weight = p.Weight
+	elem.selfClip = p.Clip
+	elem.shrink, elem.hasShrink = math.Max(finite(p.ShrinkWeight), 0), true
+	elem.minW, elem.minH = finite(p.MinWidth), finite(p.MinHeight)
+}
+
+// 元素在 uiElements 中的索引，找不到返回 -1
+// 先按 id 作为下标查找, 否则查 ids, 所有元素都由 NewElement 登记, 不需要线性查找
+func (lyt *LayoutManager) index(id ID) int {
+	if size := len(lyt.uiElements); size > int(id) && id >= 0 {
+		if lyt.uiElements[id].id == id {
+			return int(id)
 		}
 	}
+	if i, ok := lyt.lookup(id); ok {
+		return i
+	}
+	return -1
+}
+
+// 从 ids 中查找, 元素被截断(MeasureOnly)之后 ids 中可能有过期的索引, 所以要检查一下
+func (lyt *LayoutManager) lookup(id ID) (i int, ok bool) {
+	if i, ok = lyt.ids[id]; ok {
+		ok = i < len(lyt.uiElements) && lyt.uiElements[i].id == id
+	}
 	return
 }
 
+// 元素是否已经存在, 不会创建元素
+func (lyt *LayoutManager) Has(id ID) bool {
+	return lyt.index(id) >= 0
+}
+
 func (lyt *LayoutManager) Dump()  {
 	log.Println("dump elemnts:", lyt.uiElements)
 	log.Println("dump group:", lyt.groupStack)
@@ -198,66 +550,277 @@ func (lyt *LayoutManager) Dump()  {
 
 func (lyt *LayoutManager) Reset() {
 	lyt.uiElements = lyt.uiElements[:0]
+	for k := range lyt.ids {
+		delete(lyt.ids, k)
+	}
+	// 重新开始, 根布局中的元素可以再次声明
+	if len(lyt.groupStack) > 0 {
+		root := &lyt.groupStack[0]
+		lyt.groupSerial++
+		root.serial, root.hasFirst = lyt.groupSerial, false
+	}
 }
 
 // Cursor Operation
 func (lyt *LayoutManager) Move(x, y float32) *LayoutManager {
-	lyt.Cursor.X, lyt.Cursor.Y = x, y
+	lyt.Cursor.X, lyt.Cursor.Y = finite(x), finite(y)
 	return lyt
 }
 
+// 光标的位置/大小, Margin 和 Gravity, 配合 SetCursorState 临时保存和恢复光标
+func (lyt *LayoutManager) CursorState() (Bound, Margin, Gravity) {
+	c := &lyt.Cursor
+	return c.Bound, c.Margin, c.Gravity
+}
+
+// 直接恢复 CursorState 保存的值, 不会修改 dirty flag 和 owner
+func (lyt *LayoutManager) SetCursorState(b Bound, m Margin, g Gravity) *LayoutManager {
+	c := &lyt.Cursor
+	c.Bound, c.Margin, c.Gravity = b, m, g
+	return lyt
+}
+
+// 返回元素的拷贝, 不会因为 uiElements 扩容而失效
 func (lyt *LayoutManager) BoundOf(id ID) (bb Element, ok bool) {
-	if size := len(lyt.uiElements); size > int(id) {
-		if bb = lyt.uiElements[id]; bb.id == id {
-			ok = true
+	if ii := lyt.index(id); ii >= 0 {
+		bb, ok = lyt.uiElements[ii], true
+	}
+	return
+}
+
+// 一组元素绝对坐标的外接矩形, 一个元素都找不到时返回 false
+func (lyt *LayoutManager) BoundsOf(ids ...ID) (bb Bound, ok bool) {
+	for _, id := range ids {
+		elem, found := lyt.Element(id)
+		if !found {
+			continue
+		}
+		if b := lyt.absBound(elem); ok {
+			bb = bb.Union(b)
+		} else {
+			bb, ok = b, true
 		}
 	}
-	// 否则进行线性查找, 找出UI边界
 	return
 }
 
+// 把光标移到当前 Group 内容区域的 (px, py) 比例处, 比如 0.25 表示 1/4 宽
+// Group 需要设置大小(SetSize), 否则什么也不做
+func (lyt *LayoutManager) MovePercent(px, py float32) *LayoutManager {
+	if !lyt.hGroup.hasSize {
+		log.Println("gui: MovePercent in group", lyt.hGroup.id, "without size, ignored")
+		return lyt
+	}
+	cw, ch := lyt.hGroup.Content()
+	return lyt.Move(cw*finite(px), ch*finite(py))
+}
+
 func (lyt *LayoutManager) Offset(dx, dy float32) *LayoutManager {
-	lyt.Cursor.X += dx
-	lyt.Cursor.Y += dy
+	lyt.Cursor.X += finite(dx)
+	lyt.Cursor.Y += finite(dy)
 	return lyt
 }
 
 func (lyt *LayoutManager) SetGravity(x, y float32) *LayoutManager {
-	lyt.hGroup.Gravity.X = math.F32Clamp(x, 0, 1)
-	lyt.hGroup.Gravity.Y = math.F32Clamp(y, 0, 1)
+	lyt.hGroup.Gravity.X = math.F32Clamp(finite(x), 0, 1)
+	lyt.hGroup.Gravity.Y = math.F32Clamp(finite(y), 0, 1)
+	return lyt
+}
+
+// 所有子元素作为一个整体(内容块)在 Group 中对齐, 只在 Group 比内容大时有效
+// SetGravity 是每个子元素分别在内容区域中对齐, 两者可以一起使用
+func (lyt *LayoutManager) SetContentGravity(gh, gv float32) *LayoutManager {
+	lyt.hGroup.contentGravity = &Gravity{math.F32Clamp(gh, 0, 1), math.F32Clamp(gv, 0, 1)}
+	return lyt
+}
+
+// 按 SetContentGravity 移动整个内容块
+func (lyt *LayoutManager) alignContent(g *Group) {
+	if g.contentGravity == nil {
+		return
+	}
+	var (
+		cw, ch = g.Content()
+		dx = math.Max(cw-g.Size.W, 0) * g.contentGravity.X
+		dy = math.Max(ch-g.Size.H, 0) * g.contentGravity.Y
+	)
+	if dx == 0 && dy == 0 {
+		return
+	}
+	lyt.arrange(g, func(g *Group, children []*Element) {
+		for _, c := range children {
+			c.X, c.Y = c.X+dx, c.Y+dy
+		}
+	})
+}
+
+// 线性布局中每个子元素至少占据 size 的空间: 垂直列表中是每一行的最小高度,
+// 水平列表中是每一列的最小宽度(相对于行内容的方向, 这是交叉轴).
+// 比预留空间小的元素按 Gravity 摆放, 比如 SetGravity(0, .5) 垂直居中
+func (lyt *LayoutManager) SetChildMinCross(size float32) *LayoutManager {
+	lyt.hGroup.minCross = size
+	return lyt
+}
+
+// 当前 Group 裁剪(Property.Clip)时使用圆角, 子元素的圆角裁剪使用 PushClipRadius
+func (lyt *LayoutManager) SetCornerRadius(r float32) *LayoutManager {
+	lyt.hGroup.radius = math.Max(finite(r), 0)
+	return lyt
+}
+
+// 测量之后把当前 Group 变成正方形, 边长取宽和高中较大的一个, 比如徽章
+// 内容在多出来的空间中的位置由 SetContentGravity 决定
+func (lyt *LayoutManager) SetSquare(square bool) *LayoutManager {
+	lyt.hGroup.square = square
+	return lyt
+}
+
+// 限制当前 Group 测量后的最小大小, 0 表示这个方向不限制
+func (lyt *LayoutManager) SetMinSize(w, h float32) *LayoutManager {
+	lyt.hGroup.minSize.W, lyt.hGroup.minSize.H = finite(w), finite(h)
+	return lyt
+}
+
+// 限制当前 Group 测量后的最大大小, 0 表示这个方向不限制
+// 超出的内容可以滚动(SetScroll)或者裁剪(PushClip), 见 Overflowed
+func (lyt *LayoutManager) SetMaxSize(w, h float32) *LayoutManager {
+	lyt.hGroup.maxSize.W, lyt.hGroup.maxSize.H = finite(w), finite(h)
 	return lyt
 }
 
+// 把大小限制在 [minSize, maxSize] 之内, 最大值优先
+func (g *Group) clampSize() {
+	if g.minSize.W > 0 {
+		g.W = math.Max(g.W, g.minSize.W)
+	}
+	if g.minSize.H > 0 {
+		g.H = math.Max(g.H, g.minSize.H)
+	}
+	if g.maxSize.W > 0 {
+		g.W = math.Min(g.W, g.maxSize.W)
+	}
+	if g.maxSize.H > 0 {
+		g.H = math.Min(g.H, g.maxSize.H)
+	}
+}
+
 func (lyt *LayoutManager) SetSize(w, h float32) *LayoutManager {
-	lyt.hGroup.Bound.W = w
-	lyt.hGroup.Bound.H = h
+	lyt.hGroup.Bound.W = finite(w)
+	lyt.hGroup.Bound.H = finite(h)
 	lyt.hGroup.hasSize = true
 	return lyt
 }
 
 func (lyt *LayoutManager) SetPadding(top, left, right, bottom float32) *LayoutManager{
 	lyt.hGroup.Padding = Padding{left, right, top, bottom}
+	if lyt.gridCell > 0 {
+		p := &lyt.hGroup.Padding
+		p.Left, p.Right, p.Top, p.Bottom = lyt.toGrid(p.Left), lyt.toGrid(p.Right), lyt.toGrid(p.Top), lyt.toGrid(p.Bottom)
+	}
+	return lyt
+}
+
+// 按书写方向设置 padding, start/end 在 LTR 下是 left/right, 在 RTL 下是 right/left
+// EndLayout 时按当前的 Horizontal 方向解析, 同一套参数可以同时用于两种方向
+func (lyt *LayoutManager) SetPaddingLogical(start, end, top, bottom float32) *LayoutManager {
+	g := lyt.hGroup
+	g.logical, g.start, g.end = true, finite(start), finite(end)
+	if lyt.RTL() {
+		start, end = end, start
+	}
+	return lyt.SetPadding(top, start, end, bottom)
+}
+
+// 从右到左的书写方向, 即 Horizontal = Right2Left
+func (lyt *LayoutManager) SetRTL(rtl bool) *LayoutManager {
+	if rtl {
+		lyt.Horizontal = Right2Left
+	} else {
+		lyt.Horizontal = Left2Right
+	}
+	return lyt
+}
+
+func (lyt *LayoutManager) RTL() bool {
+	return lyt.Horizontal == Right2Left
+}
+
+// 按 EndLayout 时的书写方向解析 SetPaddingLogical, 左边的 padding 变化后平移子元素
+func (lyt *LayoutManager) resolvePadding(g *Group) {
+	if !g.logical {
+		return
+	}
+	left, right := g.start, g.end
+	if lyt.RTL() {
+		left, right = right, left
+	}
+	if lyt.gridCell > 0 {
+		left, right = lyt.toGrid(left), lyt.toGrid(right)
+	}
+	dx := left - g.Padding.Left
+	g.Padding.Left, g.Padding.Right = left, right
+	if dx == 0 {
+		return
+	}
+	lyt.arrange(g, func(g *Group, children []*Element) {
+		for _, c := range children {
+			c.X += dx
+		}
+	})
+}
+
+// 背景(阴影/光晕)超出 Group 的部分, 只影响 Background 返回的区域,
+// 不影响 Group 的大小和子元素的位置
+func (lyt *LayoutManager) SetBackgroundInset(m Margin) *LayoutManager {
+	lyt.hGroup.inset = m
 	return lyt
 }
 
+// Group 背景的绝对坐标, 即 Group 的区域向外扩展 inset
+func (lyt *LayoutManager) Background(id ID) (b Bound, ok bool) {
+	var elem *Element
+	if elem, ok = lyt.Element(id); ok {
+		a, m := lyt.absBound(elem), elem.inset
+		b = Bound{a.X - m.Left, a.Y - m.Top, a.W + m.Left + m.Right, a.H + m.Top + m.Bottom}
+	}
+	return
+}
+
 // AutoLayout System
 func (lyt *LayoutManager) NewLayout(id ID, xtype LayoutType) *Element {
 	return lyt.NewElement(id)
 }
 
 func (lyt *LayoutManager) FindLayout(id ID) (bb *Element, ok bool) {
-	return lyt.Element(id)
+	if bb, ok = lyt.Element(id); !ok {
+		bb, ok = lyt.revive(id)
+	}
+	return
 }
 
 // Set as current layout
 func (lyt *LayoutManager) PushLayout(xtype LayoutType, bb *Element) {
+	// 未注册的布局类型不会摆放任何元素, 按垂直布局处理
+	if _, ok := layouters[xtype]; !ok {
+		log.Println("gui: unknown layout type", xtype, "of group", bb.id, "fallback to", LinearVertical)
+		xtype = LinearVertical
+	}
+	lyt.applyExtra()
+	lyt.rootPass(bb.id)
 	ii := len(lyt.groupStack)
 
 	// group-stack has a default parent
 	// so it's safe to index
 	parent := &lyt.groupStack[ii-1]
-	lyt.groupStack = append(lyt.groupStack, Group{LayoutType:xtype, Element: bb})
+	lyt.groupStack = append(lyt.groupStack, Group{LayoutType:xtype, Element: bb, Spacing: lyt.spacing})
 	lyt.hGroup = &lyt.groupStack[ii]
+	lyt.groupSerial++
+	lyt.hGroup.serial = lyt.groupSerial
+	bb.parent, bb.group, bb.layout, bb.kids = parent.id, true, xtype, nil
+	if lyt.gridCell > 0 {
+		lyt.hGroup.Spacing = lyt.toGrid(lyt.hGroup.Spacing)
+	}
+	bb.setClip(lyt.currentClip())
 
 	// stash cursor state
 	parent.Cursor.X = lyt.Cursor.X
@@ -266,23 +829,69 @@ func (lyt *LayoutManager) PushLayout(xtype LayoutType, bb *Element) {
 	// group's (x, y) is absolute coordinate
 	// f(x, y) = group(x, y) + cursor(x, y)
 	g := lyt.hGroup
-	g.X, g.Y = parent.X, parent.Y
+	g.X, g.Y = parent.X-parent.scroll[0]+parent.Padding.Left, parent.Y-parent.scroll[1]+parent.Padding.Top
 	g.X, g.Y = g.X+lyt.Cursor.X , g.Y+lyt.Cursor.Y
 
+	if lyt.gridCell > 0 {
+		g.X, g.Y = lyt.toGrid(g.X), lyt.toGrid(g.Y)
+	}
+
 	// reset cursor
 	lyt.Cursor.X, lyt.Cursor.Y = 0, 0
 }
 
 // PopLayout, resume parent's state
 func (lyt *LayoutManager) EndLayout() {
+	lyt.resolvePadding(lyt.hGroup)
+	// 0. 需要重新摆放子元素的布局
+	if a, ok := layouters[lyt.hGroup.LayoutType].(Arranger); ok {
+		lyt.arrange(lyt.hGroup, a.Arrange)
+	}
+	lyt.measureCross(lyt.hGroup)
+	lyt.solveCoupled(lyt.hGroup)
+	lyt.sortChildren(lyt.hGroup)
+	lyt.layoutTabs(lyt.hGroup)
+	lyt.distribute(lyt.hGroup)
+	lyt.shrink(lyt.hGroup)
+	lyt.applyUnderflow(lyt.hGroup)
+	lyt.applyBaselineGrid(lyt.hGroup)
+	if lyt.gridCell > 0 {
+		lyt.snapChildren(lyt.hGroup)
+	}
+	ii := lyt.index(lyt.hGroup.id)
+
 	// 1. Set size if not set explicitly
-	size := lyt.hGroup.Size
+	// 大小 = padding + 子元素 + (n-1)*spacing
+	var (
+		pad  = lyt.hGroup.Padding
+		size = lyt.hGroup.Size
+	)
+	size.W += pad.Left + pad.Right
+	size.H += pad.Top + pad.Bottom
+	lyt.hGroup.content = Bound{W: size.W, H: size.H}
+	fixedW, fixedH := lyt.hGroup.hasSize && lyt.hGroup.W != 0, lyt.hGroup.hasSize && lyt.hGroup.H != 0
 	if !lyt.hGroup.hasSize || lyt.hGroup.W == 0 {
 		lyt.hGroup.W = size.W
 	}
 	if !lyt.hGroup.hasSize || lyt.hGroup.H == 0 {
 		lyt.hGroup.H = size.H
 	}
+	if g := lyt.hGroup; g.square {
+		g.W = math.Max(g.W, g.H)
+		g.H = g.W
+	}
+	lyt.hGroup.clampSize()
+	if lyt.gridCell > 0 {
+		lyt.hGroup.W, lyt.hGroup.H = lyt.ceilGrid(lyt.hGroup.W), lyt.ceilGrid(lyt.hGroup.H)
+	}
+	size.W, size.H = lyt.hGroup.W, lyt.hGroup.H
+	lyt.alignContent(lyt.hGroup)
+	lyt.clampChildren(lyt.hGroup)
+
+	if ii >= 0 {
+		lyt.uiElements[ii].kids = lyt.hGroup.children
+		lyt.uiElements[ii].fixedW, lyt.uiElements[ii].fixedH = fixedW, fixedH
+	}
 
 	// 2. return to parent
 	if size := len(lyt.groupStack); size > 1 {
@@ -292,9 +901,13 @@ func (lyt *LayoutManager) EndLayout() {
 
 	g := lyt.hGroup
 	lyt.Cursor.X, lyt.Cursor.Y = g.Cursor.X, g.Cursor.Y
-
-	// 3. end layout, remove default spacing
-	elem := &Element{Bound:Bound{0, 0, size.W-lyt.spacing*2, size.H-lyt.spacing*2}}
+	// 3. end layout, group 作为一个元素加入父容器
+	elem := &Element{Bound:Bound{0, 0, size.W, size.H}}
+	if ii >= 0 {
+		g.children = append(g.children, ii)
+		lyt.uiElements[ii].declaredIn = g.serial
+		lyt.measureLead(g, &lyt.uiElements[ii], elem)
+	}
 
 	lyt.Extend(elem)
 	lyt.Advance(elem)
@@ -303,48 +916,377 @@ func (lyt *LayoutManager) EndLayout() {
 	lyt.Cursor.Reset()
 }
 
-// 重新计算父容器的大小
-// size + margin = BoundingBox
-func (lyt *LayoutManager) Extend(elem *Element) {
-	var (
-		g  = lyt.hGroup
-		dx = elem.W + elem.Left + elem.Right + lyt.spacing + lyt.spacing
-		dy = elem.H + elem.Top + elem.Bottom + lyt.spacing + lyt.spacing
-	)
+// 计算单个UI元素
+// 如果有大小则记录出偏移和Margin
+// 否则只返回元素
+func (lyt *LayoutManager) BeginElement(id ID) (elem *Element, ok bool) {
+	lyt.applyExtra()
+	lyt.rootPass(id)
+	if elem, ok = lyt.Element(id); !ok {
+		elem, ok = lyt.revive(id)
+	}
+	if ok && lyt.declared(elem) {
+		return lyt.duplicate(elem), false
+	}
+	if !ok {
+		elem = lyt.NewElement(id)
+		lyt.match.valid = false
+	} else {
+		var sized bool
+		explicit := elem.ownMargin
+
+		// 元素自己的属性
+		if lyt.Cursor.owner == id {
+			// 计算 Margin
+			if lyt.Cursor.Flag & FlagMargin != 0 {
+				elem.Margin = lyt.Cursor.Margin
+				explicit = true
+			}
+
+			// 计算大小
+			if lyt.Cursor.Flag & FlagSize != 0 {
+				elem.Bound.W = lyt.Cursor.W
+				elem.Bound.H = lyt.Cursor.H
+				sized = true
+			}
+
+			// 元素自己的 Gravity
+			if lyt.Cursor.Flag & FlagGravity != 0 {
+				gravity := lyt.Cursor.Gravity
+				elem.gravity = &gravity
+			}
+
+			// 锚点
+			if lyt.Cursor.Flag & FlagAnchor != 0 {
+				elem.anchorH, elem.anchorV = lyt.Cursor.anchorH, lyt.Cursor.anchorV
+				lyt.Cursor.anchorH, lyt.Cursor.anchorV = anchor{}, anchor{}
+			}
+
+			// 清空标记
+			lyt.Cursor.owner = -1
+			lyt.Cursor.Flag = 0
+		}
+		// 没有自己的 Margin 时使用 Group 的默认值
+		if m := lyt.hGroup.childMargin; m != nil && !explicit {
+			elem.Margin = *m
+		}
+		if lyt.applyMatch(elem) {
+			sized = true
+		}
+		if elem.vw > 0 || elem.vh > 0 {
+			lyt.applyViewport(elem)
+			sized = true
+		}
+		if lyt.applyAspect(elem) {
+			sized = true
+		}
+
+		// 计算偏移, 光标相对于 Group 的内容区域(去掉 padding)
+		pad := &lyt.hGroup.Padding
+		elem.X = pad.Left + lyt.Cursor.X + elem.Left
+		elem.Y = pad.Top + lyt.Cursor.Y + elem.Top
+
+		// 测量内容
+		if elem.measure != nil {
+			lyt.measure(elem, sized)
+		}
+		if !sized {
+			lyt.applyDefaultContent(elem)
+		}
+
+		// Gravity
+		var (
+			group = lyt.hGroup
+			gravity = group.gravityOf(elem)
+			cw, ch = group.Content()
+			dx, dy = group.Extent(elem)
+		)
+		ox, oy := group.slotOffset(elem)
+		elem.X, elem.Y = elem.X+ox, elem.Y+oy
+		switch group.LayoutType {
+		case LinearHorizontal:
+			elem.Y += (ch - dy) * gravity.Y
+		case LinearVertical:
+			elem.X += (cw - dx) * gravity.X
+		case LinearOverLay:
+			elem.Y += (ch - dy) * gravity.Y
+			elem.X += (cw - dx) * gravity.X
+		}
+		group.applyAnchor(elem)
+		if lyt.gridCell > 0 {
+			lyt.snapToGrid(elem)
+		}
+	}
+	elem.parent = lyt.hGroup.id
+	elem.setClip(lyt.currentClip())
+	lyt.applyDrag(elem)
+	return
+}
+
+// 结束绘制, 每绘制完一个元素都要偏移一下光标
+func (lyt *LayoutManager) EndElement(elem *Element) {
+	if elem == &lyt.discard {
+		return
+	}
+	elem.declaredIn = lyt.hGroup.serial
+	// 大小可能在 BeginElement 之后才设置
+	if lyt.gridCell > 0 {
+		elem.W, elem.H = lyt.ceilGrid(elem.W), lyt.ceilGrid(elem.H)
+	}
+	lyt.checkElement(elem)
+	if lyt.isDragged(elem) {
+		return
+	}
+	lyt.measureLead(lyt.hGroup, elem, elem)
+	lyt.Advance(elem)
+	lyt.Extend(elem)
+	if ii := lyt.index(elem.id); ii >= 0 {
+		lyt.hGroup.children = append(lyt.hGroup.children, ii)
+	}
+}
+
+// 把元素放到屏幕的绝对坐标上, 比如跟随场景物体的 HUD
+// 元素不参与当前 Group 的排列, 不影响光标和 Group 的大小, 也不受 Group 的裁剪,
+// 但是仍然可以被 HitTest 和 Visit 找到. size 只使用 W/H
+func (lyt *LayoutManager) PlaceAt(id ID, screen mgl32.Vec2, size Bound) *Element {
+	elem, ok := lyt.Element(id)
+	if !ok {
+		if elem, ok = lyt.revive(id); !ok {
+			elem = lyt.NewElement(id)
+		}
+	}
+	elem.X, elem.Y = finite(screen[0]), finite(screen[1])
+	elem.W, elem.H = math.Max(finite(size.W), 0), math.Max(finite(size.H), 0)
+	// parent 指向自己表示坐标是绝对坐标, 见 absBound
+	elem.parent = id
+	elem.setClip(clipRect{}, false)
+	return elem
+}
+
+// 当前 Group 子元素默认的 Margin, 对没有设置 Margin(Cursor.SetMargin 或 SetProperty)的子元素有效
+func (lyt *LayoutManager) SetChildMargin(m Margin) *LayoutManager {
+	lyt.hGroup.childMargin = &m
+	return lyt
+}
+
+// 设置当前 Group 子元素之间的间隔, 负数表示后一个元素和前一个重叠
+// 比如叠在一起的头像
+func (lyt *LayoutManager) SetSpacing(s float32) *LayoutManager {
+	lyt.hGroup.Spacing = s
+	return lyt
+}
+
+// 以字体大小(em)为单位设置间隔, 间隔 = factor * emPx, 比如 SetSpacingEm(.5, fontSize)
+// 字体大小变化时间隔按比例变化, 保持排版的节奏
+func (lyt *LayoutManager) SetSpacingEm(factor, emPx float32) *LayoutManager {
+	return lyt.SetSpacing(finite(factor) * finite(emPx))
+}
+
+// 在下一个元素(或者子 Group)之前增加额外的间隔, 只对线性布局有效
+// 比如菜单里分隔开的一组选项
+func (lyt *LayoutManager) SpacingBefore(extra float32) *LayoutManager {
+	lyt.hGroup.extra += extra
+	return lyt
+}
+
+// 在 fn 中声明的元素使用间隔 s, 结束后恢复原来的间隔
+// 元素之前的间隔由声明它时的 spacing 决定, 所以块内第一个元素和前一个元素之间也是 s,
+// 块后第一个元素和块内最后一个元素之间恢复原来的间隔. 只对线性布局有效.
+func (lyt *LayoutManager) WithSpacing(s float32, fn func()) {
+	ii := len(lyt.groupStack) - 1
+	g := &lyt.groupStack[ii]
+	old := g.Spacing
+	lyt.respace(g, s-old)
+	g.Spacing = s
+	fn()
+	// fn 中 PushLayout 可能会重新分配 groupStack
+	g = &lyt.groupStack[ii]
+	lyt.respace(g, old-s)
+	g.Spacing = old
+}
+
+// 前一个元素摆放时已经按旧的 spacing 移动了光标, 修正这个差值
+func (lyt *LayoutManager) respace(g *Group, delta float32) {
+	if g.count == 0 {
+		return
+	}
+	switch g.LayoutType {
+	case LinearHorizontal:
+		lyt.Cursor.X += delta
+	case LinearVertical:
+		lyt.Cursor.Y += delta
+	}
+}
 
+// 把 SpacingBefore 的间隔加到光标和 Group 的大小上
+func (lyt *LayoutManager) applyExtra() {
+	g := lyt.hGroup
 	switch g.LayoutType {
 	case LinearHorizontal:
-		// 水平加之，高度取最大
-		g.Size.W += dx
-		g.Size.H = math.Max(g.Size.H, dy)
+		lyt.Cursor.X += g.extra
+		g.Size.W += g.extra
 	case LinearVertical:
-		// 高度加之，水平取最大
-		g.Size.W = math.Max(g.Size.W, dx)
-		g.Size.H += dy
-	case LinearOverLay:
-		// 重叠, 取高或者宽的最大值
-		g.Size.W = math.Max(g.Size.W, dx)
-		g.Size.H = math.Max(g.Size.H, dy)
+		lyt.Cursor.Y += g.extra
+		g.Size.H += g.extra
 	}
+	g.extra = 0
 }
 
-// 重新计算父容器的光标位置
-func (lyt *LayoutManager) Advance(elem *Element) {
+// 预留一块空间, 像一个元素一样移动光标和扩展 Group, 但是不创建元素
+// 比如虚拟列表只创建可见的行, 用它来占据不可见的行
+func (lyt *LayoutManager) ReserveSize(w, h float32) {
+	elem := &Element{Bound: Bound{W: w, H: h}}
+	lyt.Advance(elem)
+	lyt.Extend(elem)
+}
+
+// 元素的绝对坐标
+// Group 的 (x, y) 本身就是绝对坐标, 其它元素相对于所属的 Group
+func (lyt *LayoutManager) absBound(elem *Element) Bound {
+	b := elem.Bound
+	if !elem.group && elem.parent != elem.id {
+		x, y := lyt.origin(elem.parent)
+		b.X, b.Y = b.X+x, b.Y+y
+	}
+	return b
+}
+
+// Group 内容原点的绝对坐标(计算了滚动偏移)
+func (lyt *LayoutManager) origin(id ID) (x, y float32) {
+	if p, ok := lyt.Element(id); ok {
+		x, y = p.X-p.scroll[0], p.Y-p.scroll[1]
+	}
+	return
+}
+
+// 设置了 ClampToParent 的子元素和内容区域求交集
+func (lyt *LayoutManager) clampChildren(g *Group) {
+	var clamp bool
+	for _, ii := range g.children {
+		clamp = clamp || lyt.uiElements[ii].clampToParent
+	}
+	if !clamp {
+		return
+	}
+	lyt.arrange(g, func(g *Group, children []*Element) {
+		cw, ch := g.Content()
+		box := Bound{g.Padding.Left, g.Padding.Top, cw, ch}
+		for _, c := range children {
+			if c.clampToParent {
+				c.Bound = c.Bound.Intersect(box)
+			}
+		}
+	})
+}
+
+// 重新摆放 Group 的子元素
+// 子 Group 使用绝对坐标, 摆放前转换为相对坐标, 摆放后再转换回来
+func (lyt *LayoutManager) arrange(g *Group, fn func(g *Group, children []*Element)) {
 	var (
-		g, c  = lyt.hGroup, &lyt.Cursor
-		dx = elem.W + elem.Left + elem.Right + lyt.spacing + lyt.spacing
-		dy = elem.H + elem.Top + elem.Bottom + lyt.spacing + lyt.spacing
+		children = make([]*Element, len(g.children))
+		old = make([]Bound, len(g.children))
+		ox, oy = g.X-g.scroll[0], g.Y-g.scroll[1]
 	)
+	for i, ii := range g.children {
+		c := &lyt.uiElements[ii]
+		if c.group {
+			c.X, c.Y = c.X-ox, c.Y-oy
+		}
+		children[i], old[i] = c, c.Bound
+	}
+
+	fn(g, children)
 
+	for i, c := range children {
+		if c.group {
+			dx, dy := c.X-old[i].X, c.Y-old[i].Y
+			c.X, c.Y = c.X+ox, c.Y+oy
+			lyt.shiftGroup(c.id, dx, dy)
+		}
+	}
+}
+
+// 线性布局中按主轴方向从头重新排列子元素, 保留每个元素之前的空白(见 measureLead)
+func restack(g *Group, children []*Element) {
+	var pos float32
+	for _, c := range children {
+		dx, dy := g.Extent(c)
+		pos += c.lead
+		switch g.LayoutType {
+		case LinearHorizontal:
+			ox, _ := g.slotOffset(c)
+			c.X = g.Padding.Left + pos + c.Left + ox
+			pos += dx + g.Spacing
+		case LinearVertical:
+			_, oy := g.slotOffset(c)
+			c.Y = g.Padding.Top + pos + c.Top + oy
+			pos += dy + g.Spacing
+		}
+	}
+}
+
+// 记录元素和前一个元素之间多出来的空白(ReserveSize, Offset, SpacingBefore 等),
+// 在 Advance 之前调用, 这时光标还在元素的起点
+func (lyt *LayoutManager) measureLead(g *Group, elem, extent *Element) {
+	dx, dy := g.Extent(extent)
+	pos := lyt.Cursor.Y
 	switch g.LayoutType {
 	case LinearHorizontal:
-		// 水平步进，前进一个控件宽度
-		c.X += dx
+		pos, dy = lyt.Cursor.X, dx
 	case LinearVertical:
-		// 垂直步进，前进一个控件高度
-		c.Y += dy
-	case LinearOverLay:
-		// 保持原来的位置不变..
+	default:
+		return
+	}
+	elem.lead = pos - g.next
+	g.next = pos + dy + g.Spacing
+}
+
+// 移动 Group 下所有的子 Group
+func (lyt *LayoutManager) shiftGroup(id ID, dx, dy float32) {
+	if dx == 0 && dy == 0 {
+		return
+	}
+	for i := range lyt.uiElements {
+		if e := &lyt.uiElements[i]; e.group && e.parent == id && e.id != id {
+			e.X, e.Y = e.X+dx, e.Y+dy
+			lyt.shiftGroup(e.id, dx, dy)
+		}
+	}
+}
+
+// 把屏幕坐标转换为元素的局部坐标(相对于元素的左上角)
+// 如果 id 是一个 Group，返回 Group 内容的坐标(计算了滚动偏移),
+// 这样可以直接和子元素的相对坐标比较
+func (lyt *LayoutManager) LocalPoint(id ID, p mgl32.Vec2) mgl32.Vec2 {
+	elem, ok := lyt.Element(id)
+	if !ok {
+		return p
+	}
+	b := lyt.absBound(elem)
+	p = mgl32.Vec2{p[0]-b.X, p[1]-b.Y}
+	if elem.group {
+		p = p.Add(elem.scroll)
+	}
+	return p
+}
+
+// 重新计算父容器的大小
+// size + margin = BoundingBox
+func (lyt *LayoutManager) Extend(elem *Element) {
+	lyt.roundElem(elem)
+	g := lyt.hGroup
+	if l, ok := layouters[g.LayoutType]; ok {
+		l.Measure(g, elem)
+	}
+	g.count++
+}
+
+// 重新计算父容器的光标位置
+func (lyt *LayoutManager) Advance(elem *Element) {
+	lyt.roundElem(elem)
+	g := lyt.hGroup
+	if l, ok := layouters[g.LayoutType]; ok {
+		l.Place(g, elem, &lyt.Cursor.Bound)
 	}
 }
 
@@ -364,6 +1306,126 @@ type Group struct {
 	Size struct{W, H float32}
 	Gravity struct{X, Y float32}
 
+	// 子元素之间的间隔
+	Spacing float32
+	// 已经测量过的子元素个数
+	count int
+
+	// 子元素在 uiElements 中的索引, 按加入的顺序
+	children []int
+
+	// 网格布局的参数
+	grid gridOption
+
+	// 下一个元素之前额外的间隔, 见 SpacingBefore
+	extra float32
+
+	// 每个子元素占据的最小大小, 见 SetChildMinCross
+	minCross float32
+
+	// 所有子元素作为一个整体在 Group 中的位置, 见 SetContentGravity
+	contentGravity *Gravity
+
+	// 按权重分配剩余空间的空白, 见 Spacer
+	spacers []spacer
+
+	// 子元素超出 Group 时收缩, 见 SetShrinkToFit
+	shrink bool
+
+	// 子元素填不满 Group 时空白的位置, 见 SetUnderflow
+	underflow Underflow
+
+	// 测量后的大小限制, 0 表示不限制, 见 SetMinSize/SetMaxSize
+	minSize, maxSize struct{W, H float32}
+
+	// 按声明的相反顺序排列, 见 SetReverse
+	reverse bool
+
+	// 子元素默认的 Margin, 见 SetChildMargin
+	childMargin *Margin
+
+	// 宽高取较大的一边, 见 SetSquare
+	square bool
+
+	// 还没有停靠的区域, 见 Dock
+	dock Bound
+	docked bool
+
+	// 子元素顶部对齐的网格, 见 SetBaselineGrid
+	baselineGrid float32
+
+	// 标签栏, 见 SetSelectedTab
+	tabs tabStrip
+
+	// 按书写方向设置的 padding, 见 SetPaddingLogical
+	logical bool
+	start, end float32
+
+	// 每次 PushLayout 都不同的编号, 见 Element.declaredIn
+	serial int
+	// 根布局本帧的第一个子元素, 见 rootPass
+	first ID
+	hasFirst bool
+
+	// 线性布局中下一个元素不留空白时的位置(主轴方向), 见 measureLead
+	next float32
+
 	// true if group has a predefined size
 	hasSize bool
+}
+
+// 元素在 Group 中占据的大小
+// size + margin, 线性布局中不小于 SetChildMinCross 设置的最小值
+func (g *Group) Extent(elem *Element) (dx, dy float32) {
+	dx = elem.W + elem.Left + elem.Right
+	dy = elem.H + elem.Top + elem.Bottom
+	switch g.LayoutType {
+	case LinearHorizontal:
+		dx = math.Max(dx, g.minCross)
+	case LinearVertical:
+		dy = math.Max(dy, g.minCross)
+	}
+	return
+}
+
+// 元素比 SetChildMinCross 预留的空间小时, 按 Gravity 在预留的空间内的偏移
+func (g *Group) slotOffset(elem *Element) (ox, oy float32) {
+	if g.minCross <= 0 {
+		return
+	}
+	var (
+		gravity = g.gravityOf(elem)
+		dx, dy = g.Extent(elem)
+	)
+	switch g.LayoutType {
+	case LinearHorizontal:
+		ox = (dx - elem.W - elem.Left - elem.Right) * gravity.X
+	case LinearVertical:
+		oy = (dy - elem.H - elem.Top - elem.Bottom) * gravity.Y
+	}
+	return
+}
+
+// 子元素之间的间隔, spacing 只出现在兄弟元素之间, 第一个元素之前没有
+// 在 Measure 里使用
+func (g *Group) Gap() float32 {
+	if g.count > 0 {
+		return g.Spacing
+	}
+	return 0
+}
+
+// 元素的 Gravity 覆盖 Group 的 Gravity
+func (g *Group) gravityOf(elem *Element) Gravity {
+	if elem.gravity != nil {
+		return *elem.gravity
+	}
+	return Gravity(g.Gravity)
+}
+
+// 内容区域的大小(去掉 padding)
+func (g *Group) Content() (w, h float32) {
+	w = g.W - g.Padding.Left - g.Padding.Right
+	h = g.H - g.Padding.Top - g.Padding.Bottom
+	return
 }
\ No newline at end of file
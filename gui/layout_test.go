@@ -0,0 +1,964 @@
+package gui
+
+import (
+	geo "math"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestCursorMargin(t *testing.T) {
+	c := &cursor{}
+
+	c.SetMargin(1, 2, 3, 4)
+	if m := c.Margin; m.Top != 1 || m.Left != 2 || m.Right != 3 || m.Bottom != 4 {
+		t.Error("SetMargin(top, left, right, bottom) mismatch:", m)
+	}
+	if c.Flag&FlagMargin == 0 {
+		t.Error("SetMargin should set FlagMargin")
+	}
+
+	c = &cursor{}
+	c.MarginTop(1).MarginLeft(2).MarginRight(3).MarginBottom(4)
+	if m := c.Margin; m.Top != 1 || m.Left != 2 || m.Right != 3 || m.Bottom != 4 {
+		t.Error("per-side margin mismatch:", m)
+	}
+	if c.Flag&FlagMargin == 0 {
+		t.Error("per-side setter should set FlagMargin")
+	}
+
+	c.MarginXY(5, 6)
+	if m := c.Margin; m.Left != 5 || m.Right != 5 || m.Top != 6 || m.Bottom != 6 {
+		t.Error("MarginXY mismatch:", m)
+	}
+}
+
+type diagonalLayout struct {
+	measured, placed int
+}
+
+func (d *diagonalLayout) Measure(g *Group, elem *Element) {
+	dx, dy := g.Extent(elem)
+	g.Size.W += dx
+	g.Size.H += dy
+	d.measured++
+}
+
+func (d *diagonalLayout) Place(g *Group, elem *Element, c *Bound) {
+	dx, dy := g.Extent(elem)
+	c.X += dx
+	c.Y += dy
+	d.placed++
+}
+
+func TestRegisterLayout(t *testing.T) {
+	const Diagonal LayoutType = 100
+	diagonal := &diagonalLayout{}
+	RegisterLayout(Diagonal, diagonal)
+	defer delete(layouters, Diagonal)
+
+	lyt := New()
+	lyt.PushLayout(Diagonal, lyt.NewLayout(1, Diagonal))
+
+	for i := 0; i < 2; i++ {
+		elem := lyt.NewElement(ID(2 + i))
+		elem.Size(10, 10)
+		lyt.Advance(elem)
+		lyt.Extend(elem)
+	}
+
+	if diagonal.measured != 2 || diagonal.placed != 2 {
+		t.Error("custom layouter not invoked:", diagonal.measured, diagonal.placed)
+	}
+	if c := lyt.Cursor; c.X != 20 || c.Y != 20 {
+		t.Error("diagonal cursor:", c.X, c.Y)
+	}
+	if size := lyt.hGroup.Size; size.W != 20 || size.H != 20 {
+		t.Error("diagonal group size:", size)
+	}
+}
+
+func TestLocalPoint(t *testing.T) {
+	lyt := New()
+
+	lyt.Move(100, 50)
+	lyt.PushLayout(LinearVertical, lyt.NewLayout(1, LinearVertical))
+	lyt.SetScroll(1, 0, 30)
+
+	elem := lyt.NewElement(2)
+	elem.parent = 1
+	elem.Bound = Bound{10, 20, 40, 40}
+
+	// element: (100+10, 50+20-30)
+	if p := lyt.LocalPoint(2, mgl32.Vec2{115, 45}); p != (mgl32.Vec2{5, 5}) {
+		t.Error("local point of element:", p)
+	}
+	// group content: (115-100, 45-50+30)
+	if p := lyt.LocalPoint(1, mgl32.Vec2{115, 45}); p != (mgl32.Vec2{15, 25}) {
+		t.Error("local point of group:", p)
+	}
+}
+
+// find or create a layout element, as Context.BeginLayout does
+func layoutOf(lyt *LayoutManager, id ID) *Element {
+	if elem, ok := lyt.FindLayout(id); ok {
+		return elem
+	}
+	return lyt.NewLayout(id, LinearVertical)
+}
+
+func TestGapSpacing(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 10
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		lyt.SetPadding(5, 5, 5, 5)
+		for id := ID(2); id <= 4; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(20, 20)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	// 3 children + 2 gaps + padding, no gap at the edges
+	if g, _ := lyt.Element(1); g.W != 60+20+2*5 || g.H != 20+2*5 {
+		t.Error("group size:", g.Bound)
+	}
+	for i, x := range []float32{5, 35, 65} {
+		if elem, _ := lyt.Element(ID(2 + i)); elem.X != x || elem.Y != 5 {
+			t.Error("child", i, "placed at:", elem.X, elem.Y, "expected:", x)
+		}
+	}
+}
+
+func TestReserveSize(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.ReserveSize(0, 500)
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(100, 20)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	if elem, _ := lyt.Element(2); elem.Y != 500+lyt.spacing {
+		t.Error("row after reserved space:", elem.Y)
+	}
+	if g, _ := lyt.Element(1); g.H != 500+lyt.spacing+20 {
+		t.Error("group height with reserved space:", g.H)
+	}
+	if len(lyt.uiElements) != 3 {
+		t.Error("reserved space should not create element")
+	}
+}
+
+func TestBackgroundInset(t *testing.T) {
+	lyt := New()
+
+	frame := func(inset Margin) {
+		lyt.Move(10, 10)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetBackgroundInset(inset)
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(40, 20)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame(Margin{})
+	frame(Margin{})
+	child, _ := lyt.Element(2)
+	plain := child.Bound
+
+	frame(Margin{Top: 1, Left: 2, Bottom: 3, Right: 4})
+	if b, _ := lyt.Background(1); b != (Bound{8, 9, 46, 24}) {
+		t.Error("background:", b)
+	}
+	if g, _ := lyt.Element(1); g.Bound != (Bound{10, 10, 40, 20}) {
+		t.Error("group bound should not change:", g.Bound)
+	}
+	if child.Bound != plain {
+		t.Error("child should not be affected:", child.Bound, plain)
+	}
+}
+
+func TestFrameLayout(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(FrameLayout, layoutOf(lyt, 1))
+		lyt.SetPadding(5, 5, 5, 5)
+
+		bg, _ := lyt.BeginElement(2)
+		bg.Size(100, 100)
+		lyt.EndElement(bg)
+
+		lyt.Cursor.SetGravity(.5, .5).To(3)
+		fg, _ := lyt.BeginElement(3)
+		fg.Size(20, 20)
+		lyt.EndElement(fg)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	if g, _ := lyt.Element(1); g.W != 110 || g.H != 110 {
+		t.Error("frame should size to the largest child:", g.Bound)
+	}
+	if bg, _ := lyt.Element(2); bg.X != 5 || bg.Y != 5 {
+		t.Error("background placed at:", bg.Bound)
+	}
+	if fg, _ := lyt.Element(3); fg.X != 45 || fg.Y != 45 {
+		t.Error("foreground should be centered:", fg.Bound)
+	}
+}
+
+func TestClampToParent(t *testing.T) {
+	lyt := New()
+
+	clamp := NewProperty()
+	clamp.ClampToParent = true
+	clamp.Width, clamp.Height = 150, 10
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetSize(100, 40)
+		lyt.SetPadding(5, 5, 5, 5)
+		for id := ID(2); id <= 3; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(150, 10)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	lyt.SetProperty(2, clamp)
+	frame()
+
+	if elem, _ := lyt.Element(2); elem.Bound != (Bound{5, 5, 90, 10}) {
+		t.Error("child should be clamped to the content box:", elem.Bound)
+	}
+	if elem, _ := lyt.Element(3); elem.W != 150 {
+		t.Error("child without ClampToParent should overflow:", elem.Bound)
+	}
+}
+
+func TestSpacingBefore(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		for id := ID(2); id <= 4; id++ {
+			if id == 3 {
+				lyt.SpacingBefore(10)
+			}
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(20, 20)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	sp := lyt.spacing
+	for i, y := range []float32{0, 20 + sp + 10, 40 + 2*sp + 10} {
+		if elem, _ := lyt.Element(ID(2 + i)); elem.Y != y {
+			t.Error("child", i, "placed at:", elem.Y, "expected:", y)
+		}
+	}
+	if g, _ := lyt.Element(1); g.H != 60+2*sp+10 {
+		t.Error("group height should include the extra gap:", g.H)
+	}
+}
+
+func TestWithSpacing(t *testing.T) {
+	lyt := New()
+
+	item := func(id ID) {
+		elem, _ := lyt.BeginElement(id)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+	}
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		item(2)
+		lyt.WithSpacing(0, func() {
+			item(3)
+			item(4)
+		})
+		item(5)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	sp := lyt.spacing
+	for i, x := range []float32{0, 20, 40, 60 + sp} {
+		if elem, _ := lyt.Element(ID(2 + i)); elem.X != x {
+			t.Error("child", i, "placed at:", elem.X, "expected:", x)
+		}
+	}
+	if g, _ := lyt.Element(1); g.W != 80+sp {
+		t.Error("group width:", g.W)
+	}
+	if g := lyt.hGroup; g.Spacing != sp {
+		t.Error("spacing should be restored:", g.Spacing)
+	}
+}
+
+func TestChildMinCross(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetChildMinCross(48).SetGravity(0, .5)
+		for id := ID(2); id <= 3; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(100, 20)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	for i, y := range []float32{14, 62} {
+		if elem, _ := lyt.Element(ID(2 + i)); elem.Y != y || elem.H != 20 {
+			t.Error("row", i, "should be centered in 48px:", elem.Bound)
+		}
+	}
+	if g, _ := lyt.Element(1); g.H != 96 {
+		t.Error("list height should reserve 48px per row:", g.H)
+	}
+}
+
+func TestMatchSize(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		label, _ := lyt.BeginElement(2)
+		label.Size(40, 30)
+		lyt.EndElement(label)
+
+		lyt.MatchSize(2, AxisY)
+		field, _ := lyt.BeginElement(3)
+		if field.W == 0 {
+			field.Size(100, 10)
+		}
+		lyt.EndElement(field)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	if elem, _ := lyt.Element(3); elem.H != 30 || elem.W != 100 {
+		t.Error("field should match the label height:", elem.Bound)
+	}
+
+	// not placed yet, ignored
+	lyt.MatchSize(4, AxisX)
+	elem, _ := lyt.BeginElement(3)
+	if elem.W != 100 {
+		t.Error("unresolvable match should be ignored:", elem.Bound)
+	}
+	lyt.EndElement(elem)
+
+	// the target is in a sibling group that has already ended
+	sibling := func() {
+		lyt.BeginFrame()
+		defer lyt.EndFrame()
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 5))
+		label, _ := lyt.BeginElement(6)
+		label.Size(40, 30)
+		lyt.EndElement(label)
+		lyt.EndLayout()
+		lyt.MatchSize(6, AxisX)
+		elem, _ := lyt.BeginElement(7)
+		lyt.EndElement(elem)
+	}
+	sibling()
+	sibling()
+	if elem, _ := lyt.Element(7); elem.W != 40 {
+		t.Error("target in an ended sibling group should be found:", elem.Bound)
+	}
+}
+
+func TestBoundOfFallback(t *testing.T) {
+	lyt := New()
+	// index 1 holds id 7, so id 7 is not at index 7
+	lyt.NewElement(7).Bound = Bound{1, 2, 3, 4}
+	lyt.NewElement(1)
+
+	bb, ok := lyt.BoundOf(7)
+	if !ok || bb.id != 7 || bb.Bound != (Bound{1, 2, 3, 4}) {
+		t.Error("linear fallback should find the element:", bb.Bound, ok)
+	}
+	if _, ok := lyt.BoundOf(8); ok {
+		t.Error("missing element should not be found")
+	}
+	if _, ok := lyt.BoundOf(-5); ok {
+		t.Error("negative id should not be found")
+	}
+
+	// a copy, not a reference
+	bb.W = 100
+	if elem, _ := lyt.Element(7); elem.W != 3 {
+		t.Error("BoundOf should return a copy")
+	}
+}
+
+func TestContentGravity(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		lyt.SetSize(200, 100)
+		lyt.SetContentGravity(0, .5)
+		for id, h := range []float32{10, 30} {
+			elem, _ := lyt.BeginElement(ID(2 + id))
+			elem.Size(20, h)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	// block is 40x30, centered vertically: (100-30)/2
+	for i, x := range []float32{0, 20} {
+		if elem, _ := lyt.Element(ID(2 + i)); elem.X != x || elem.Y != 35 {
+			t.Error("child", i, "should move with the content block:", elem.Bound)
+		}
+	}
+}
+
+func TestNegativeSpacing(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		lyt.SetSpacing(-10)
+		for id := ID(2); id <= 6; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(30, 30)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	// each avatar overlaps the previous one by 10
+	for i := 0; i < 5; i++ {
+		if elem, _ := lyt.Element(ID(2 + i)); elem.X != float32(20*i) {
+			t.Error("avatar", i, "placed at:", elem.X)
+		}
+	}
+	if g, _ := lyt.Element(1); g.W != 5*30-4*10 {
+		t.Error("stack width:", g.W)
+	}
+
+	// overlap larger than the element never moves the cursor before the origin
+	g := &Group{LayoutType: LinearHorizontal, Spacing: -50}
+	c := &Bound{}
+	elem := &Element{Bound: Bound{W: 30, H: 30}}
+	horizontalLayout{}.Place(g, elem, c)
+	if c.X != 0 {
+		t.Error("cursor should be clamped to the origin:", c.X)
+	}
+}
+
+func TestApplyProperty(t *testing.T) {
+	c := &cursor{}
+	c.SetMargin(1, 2, 3, 4).SetGravity(.5, .5)
+
+	p := Property{Width: 100}
+	c.ApplyProperty(p, FlagSize)
+
+	if c.W != 100 || c.Flag&FlagSize == 0 {
+		t.Error("width should be applied:", c.Bound, c.Flag)
+	}
+	if m := c.Margin; m.Top != 1 || m.Left != 2 || m.Right != 3 || m.Bottom != 4 {
+		t.Error("margin should be untouched:", m)
+	}
+	if c.Gravity != (Gravity{.5, .5}) {
+		t.Error("gravity should be untouched:", c.Gravity)
+	}
+
+	c.ApplyProperty(Property{GravityH: 1}, FlagGravity)
+	if c.Gravity != (Gravity{1, 0}) {
+		t.Error("gravity in mask should be applied:", c.Gravity)
+	}
+}
+
+func TestNaNGuard(t *testing.T) {
+	lyt := New()
+	nan := float32(geo.NaN())
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetGravity(nan, 0)
+		lyt.Cursor.SetSize(nan, float32(geo.Inf(1))).To(2)
+		elem, _ := lyt.BeginElement(2)
+		lyt.EndElement(elem)
+		lyt.Offset(nan, 0)
+		lyt.Cursor.SetSize(20, 20).To(3)
+		elem, _ = lyt.BeginElement(3)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	if elem, _ := lyt.Element(2); elem.W != 0 || elem.H != 0 {
+		t.Error("NaN size should fall back to 0:", elem.Bound)
+	}
+	finite := func(v float32) bool {
+		return !geo.IsNaN(float64(v)) && !geo.IsInf(float64(v), 0)
+	}
+	lyt.Visit(func(info ElementInfo) {
+		b := info.Bound
+		if !finite(b.X) || !finite(b.Y) || !finite(b.W) || !finite(b.H) {
+			t.Error("element", info.ID, "is not finite:", b)
+		}
+	})
+}
+
+func TestAnchor(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearOverLay, layoutOf(lyt, 1))
+		lyt.SetSize(200, 100)
+		lyt.SetPadding(10, 10, 10, 10)
+
+		lyt.Cursor.AnchorRight(.3).Anchor(EdgeBottom, 0, 5).To(2)
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	// content: 180x80, right edge at 180*.7, bottom edge 5 above the content bottom
+	if elem, _ := lyt.Element(2); elem.X != 10+126-20 || elem.Y != 10+80-5-20 {
+		t.Error("anchored element:", elem.Bound)
+	}
+}
+
+func TestLayoutTypeString(t *testing.T) {
+	if s := LinearHorizontal.String(); s != "LinearHorizontal" {
+		t.Error("layout type string:", s)
+	}
+	if s := LayoutType(42).String(); s != "LayoutType(42)" {
+		t.Error("unknown layout type string:", s)
+	}
+}
+
+func TestInvalidLayoutType(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LayoutType(42), layoutOf(lyt, 1))
+		for id := ID(2); id <= 3; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(20, 20)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	if g, _ := lyt.Element(1); g.layout != LinearVertical || g.H != 40 {
+		t.Error("invalid layout type should fall back to LinearVertical:", g.layout, g.Bound)
+	}
+	if elem, _ := lyt.Element(3); elem.Y != 20 {
+		t.Error("child placed at:", elem.Bound)
+	}
+}
+
+func TestMinMaxSize(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func(n int) {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetMinSize(100, 50).SetMaxSize(0, 200)
+		for i := 0; i < n; i++ {
+			elem, _ := lyt.BeginElement(ID(2 + i))
+			elem.Size(20, 100)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame(3)
+	frame(3)
+
+	if g, _ := lyt.Element(1); g.W != 100 || g.H != 200 {
+		t.Error("group should be clamped to the max height:", g.Bound)
+	}
+	if !lyt.Overflowed(1) {
+		t.Error("content beyond the max size should be flagged")
+	}
+
+	lyt = New()
+	frame(0)
+	frame(0)
+	if g, _ := lyt.Element(1); g.W != 100 || g.H != 50 {
+		t.Error("empty group should grow to the min size:", g.Bound)
+	}
+	if lyt.Overflowed(1) {
+		t.Error("no overflow expected")
+	}
+}
+
+func TestBoundsOf(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func() {
+		lyt.Move(10, 10)
+		lyt.PushLayout(LinearOverLay, layoutOf(lyt, 1))
+		for i, b := range []Bound{{0, 0, 10, 10}, {50, 20, 10, 10}, {20, 80, 5, 5}} {
+			lyt.Move(b.X, b.Y)
+			elem, _ := lyt.BeginElement(ID(2 + i))
+			elem.Size(b.W, b.H)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	bb, ok := lyt.BoundsOf(2, 3, 4, 99)
+	if !ok || bb != (Bound{10, 10, 60, 85}) {
+		t.Error("unexpected union:", bb, ok)
+	}
+	if _, ok := lyt.BoundsOf(99); ok {
+		t.Error("unknown ids should not resolve")
+	}
+}
+
+func TestPaddingLogical(t *testing.T) {
+	for _, rtl := range []bool{false, true} {
+		// late 为 true 时在 SetPaddingLogical 之后才切换方向
+		for _, late := range []bool{false, true} {
+			lyt := New()
+			lyt.spacing = 0
+			frame := func() {
+				lyt.SetRTL(rtl && !late)
+				lyt.Move(0, 0)
+				lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+				lyt.SetPaddingLogical(10, 2, 0, 0)
+				if late {
+					lyt.SetRTL(rtl)
+				}
+				for id := ID(2); id <= 3; id++ {
+					p := NewProperty()
+					p.Width, p.Height = 20, 10
+					lyt.SetProperty(id, p)
+					elem, _ := lyt.BeginElement(id)
+					lyt.EndElement(elem)
+				}
+				lyt.EndLayout()
+			}
+			frame()
+			frame()
+
+			x0, x1 := float32(10), float32(30)
+			if rtl {
+				x0, x1 = 2, 22
+			}
+			b2, _ := lyt.BoundsOf(2)
+			b3, _ := lyt.BoundsOf(3)
+			if b2.X != x0 || b3.X != x1 {
+				t.Error("unexpected child positions, rtl:", rtl, "late:", late, b2, b3)
+			}
+			if b, _ := lyt.BoundsOf(1); b.W != 52 {
+				t.Error("group should include both paddings:", b)
+			}
+		}
+	}
+}
+
+func TestPlaceAt(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func() {
+		lyt.Move(50, 50)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+		lyt.PlaceAt(3, mgl32.Vec2{400, 300}, Bound{W: 30, H: 10})
+		elem, _ = lyt.BeginElement(4)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	if b, _ := lyt.BoundsOf(3); b != (Bound{400, 300, 30, 10}) {
+		t.Error("element should be at the screen position:", b)
+	}
+	if b, _ := lyt.BoundsOf(4); b.Y != 70 {
+		t.Error("placed element should not advance the cursor:", b)
+	}
+	if g, _ := lyt.Element(1); g.H != 40 || g.W != 20 {
+		t.Error("placed element should not extend the group:", g.Bound)
+	}
+	if id, ok := lyt.HitTest(mgl32.Vec2{410, 305}); !ok || id != 3 {
+		t.Error("placed element should be hit-testable:", id, ok)
+	}
+	var visited bool
+	lyt.Visit(func(info ElementInfo) {
+		visited = visited || info.ID == 3
+	})
+	if !visited {
+		t.Error("placed element should be visited")
+	}
+}
+
+func TestChildMargin(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetChildMargin(Margin{Top: 5, Left: 5})
+		for id := ID(2); id <= 4; id++ {
+			if id == 4 {
+				lyt.Cursor.SetMargin(0, 10, 0, 0).To(4)
+			}
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(20, 20)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	expect := map[ID]Margin{2: {Top: 5, Left: 5}, 3: {Top: 5, Left: 5}, 4: {Left: 10}}
+	for id, m := range expect {
+		if e, _ := lyt.Element(id); e.Margin != m {
+			t.Error("element", id, "margin:", e.Margin, "expected:", m)
+		}
+	}
+	if e, _ := lyt.Element(3); e.X != 5 || e.Y != 30 {
+		t.Error("inherited margin should offset the element:", e.Bound)
+	}
+
+	// 只设置了别的属性, 仍然使用默认的 Margin
+	weight, margin := NewProperty(), NewProperty()
+	weight.Weight, margin.MarginLeft = 1, 1
+	lyt.SetProperty(2, weight)
+	lyt.SetProperty(3, margin)
+	frame()
+	if e, _ := lyt.Element(2); e.Margin != (Margin{Top: 5, Left: 5}) {
+		t.Error("property without margin should keep the group default:", e.Margin)
+	}
+	if e, _ := lyt.Element(3); e.Margin != (Margin{Left: 1}) {
+		t.Error("property margin should override the group default:", e.Margin)
+	}
+}
+
+func TestMovePercent(t *testing.T) {
+	lyt := New()
+	lyt.PushLayout(LinearOverLay, layoutOf(lyt, 1))
+	lyt.MovePercent(.25, 0)
+	if lyt.Cursor.X != 0 {
+		t.Error("group without size should ignore MovePercent:", lyt.Cursor.X)
+	}
+	lyt.SetSize(200, 100).SetPadding(0, 0, 0, 0)
+	lyt.MovePercent(.25, .5)
+	if lyt.Cursor.X != 50 || lyt.Cursor.Y != 50 {
+		t.Error("cursor should move to a fraction of the group:", lyt.Cursor.Bound)
+	}
+	lyt.EndLayout()
+}
+
+func TestHas(t *testing.T) {
+	lyt := New()
+	lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+	elem, _ := lyt.BeginElement(100)
+	lyt.EndElement(elem)
+	lyt.EndLayout()
+
+	if !lyt.Has(100) || !lyt.Has(1) {
+		t.Error("registered elements should exist")
+	}
+	n := len(lyt.uiElements)
+	if lyt.Has(42) || len(lyt.uiElements) != n {
+		t.Error("unused id should not exist or be created")
+	}
+
+	// 截断之后不会返回过期的索引
+	lyt.MeasureOnly(func() {
+		elem, _ := lyt.BeginElement(200)
+		lyt.EndElement(elem)
+	})
+	if lyt.Has(200) {
+		t.Error("element created inside MeasureOnly should be gone")
+	}
+	if _, ok := lyt.ids[200]; ok {
+		t.Error("MeasureOnly should not leave stale ids behind")
+	}
+}
+
+func TestSquare(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetSquare(true).SetContentGravity(.5, .5)
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(30, 50)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	if g, _ := lyt.Element(1); g.W != 50 || g.H != 50 {
+		t.Error("group should be square:", g.Bound)
+	}
+	if e, _ := lyt.Element(2); e.X != 10 || e.Y != 0 {
+		t.Error("content should be centered:", e.Bound)
+	}
+}
+
+func TestDuplicateID(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func() {
+		lyt.BeginFrame()
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(30, 10)
+		lyt.EndElement(elem)
+		// the second declaration is dropped
+		elem, _ = lyt.BeginElement(2)
+		elem.Size(50, 50)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+		lyt.EndFrame()
+	}
+	frame()
+	frame()
+
+	var n int
+	for i := range lyt.uiElements {
+		if lyt.uiElements[i].id == 2 {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Error("expected exactly one element, got:", n)
+	}
+	if e, _ := lyt.Element(2); e.W != 30 || e.H != 10 {
+		t.Error("the first declaration should win:", e.Bound)
+	}
+	if g, _ := lyt.Element(1); g.H != 10 {
+		t.Error("the duplicate should not extend the group:", g.Bound)
+	}
+
+	// 不使用 BeginFrame 时根布局中也能检查出来, 每个 LayoutManager 各自打印日志
+	root := New()
+	rootFrame := func() {
+		for _, id := range []ID{2, 3, 3} {
+			elem, _ := root.BeginElement(id)
+			elem.Size(10, 10)
+			root.EndElement(elem)
+		}
+	}
+	rootFrame()
+	rootFrame()
+	if e, _ := root.Element(3); e.W != 10 || len(root.uiElements) != 3 {
+		t.Error("root level duplicate should be dropped:", len(root.uiElements))
+	}
+	if !root.dupLogged || !lyt.dupLogged {
+		t.Error("each manager should log its own duplicates")
+	}
+}
+
+func TestSpacingEm(t *testing.T) {
+	for _, em := range []float32{16, 24} {
+		lyt := New()
+		frame := func() {
+			lyt.Move(0, 0)
+			lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+			lyt.SetSpacingEm(.5, em)
+			for id := ID(2); id <= 3; id++ {
+				elem, _ := lyt.BeginElement(id)
+				elem.Size(10, 10)
+				lyt.EndElement(elem)
+			}
+			lyt.EndLayout()
+		}
+		frame()
+		frame()
+
+		if e, _ := lyt.Element(3); e.Y != 10+em/2 {
+			t.Error("spacing should be half an em:", em, e.Y)
+		}
+	}
+}
+
+func TestCursorState(t *testing.T) {
+	lyt := New()
+	lyt.Move(10, 20)
+	lyt.Cursor.SetSize(30, 40).SetMargin(1, 2, 3, 4).SetGravity(.5, 1)
+	b, m, g := lyt.CursorState()
+
+	lyt.Move(0, 0)
+	lyt.Cursor.SetSize(5, 5).SetMargin(0, 0, 0, 0).SetGravity(0, 0)
+	if b2, _, _ := lyt.CursorState(); b2 == b {
+		t.Fatal("cursor should have been mutated")
+	}
+
+	lyt.SetCursorState(b, m, g)
+	b2, m2, g2 := lyt.CursorState()
+	if b2 != (Bound{10, 20, 30, 40}) || b2 != b {
+		t.Error("bound not restored:", b2)
+	}
+	if m2 != (Margin{Top: 1, Left: 2, Right: 3, Bottom: 4}) || m2 != m {
+		t.Error("margin not restored:", m2)
+	}
+	if g2 != (Gravity{.5, 1}) || g2 != g {
+		t.Error("gravity not restored:", g2)
+	}
+}
@@ -0,0 +1,132 @@
+package gui
+
+import (
+	"fmt"
+
+	"korok.io/korok/engi/math"
+)
+
+// Layouter 实现一种布局算法，每个 LayoutType 对应一个 Layouter
+// Measure: 加入一个元素后，重新计算 Group 的大小
+// Place:   加入一个元素后，移动光标(c 是当前 Group 内的光标)
+//
+// 光标以 *Bound 的形式传入，这样在 gui 包外也可以实现自己的布局
+type Layouter interface {
+	Measure(g *Group, elem *Element)
+	Place(g *Group, elem *Element, c *Bound)
+}
+
+// 子元素的位置依赖所有子元素的布局(比如表格), 可以额外实现 Arranger,
+// EndLayout 时会用所有子元素调用一次 Arrange, 子元素的坐标相对于 Group,
+// Arrange 负责设置子元素的 (x, y) 和 Group 的 Size
+type Arranger interface {
+	Arrange(g *Group, children []*Element)
+}
+
+var layouters = map[LayoutType]Layouter{}
+
+// 注册(或者替换)一种布局算法
+func RegisterLayout(xtype LayoutType, l Layouter) {
+	layouters[xtype] = l
+}
+
+// 查找已注册的布局算法
+func FindLayouter(xtype LayoutType) (l Layouter, ok bool) {
+	l, ok = layouters[xtype]
+	return
+}
+
+func (t LayoutType) String() string {
+	switch t {
+	case LinearVertical:
+		return "LinearVertical"
+	case LinearHorizontal:
+		return "LinearHorizontal"
+	case LinearOverLay:
+		return "LinearOverLay"
+	case GridLayout:
+		return "GridLayout"
+	case FrameLayout:
+		return "FrameLayout"
+	case FlowLayout:
+		return "FlowLayout"
+	}
+	return fmt.Sprintf("LayoutType(%d)", int(t))
+}
+
+type horizontalLayout struct {}
+
+func (horizontalLayout) Measure(g *Group, elem *Element) {
+	// 水平加之，高度取最大
+	// spacing 为负数时元素互相重叠, 大小取实际占据的范围
+	dx, dy := g.Extent(elem)
+	g.Size.W = math.Max(g.Size.W, g.Size.W + g.Gap() + dx)
+	g.Size.H = math.Max(g.Size.H, dy)
+}
+
+func (horizontalLayout) Place(g *Group, elem *Element, c *Bound) {
+	// 水平步进，前进一个控件宽度
+	// 光标不能退到 Group 的原点之前
+	dx, _ := g.Extent(elem)
+	c.X = math.Max(c.X + dx + g.Spacing, 0)
+}
+
+type verticalLayout struct {}
+
+func (verticalLayout) Measure(g *Group, elem *Element) {
+	// 高度加之，水平取最大
+	dx, dy := g.Extent(elem)
+	g.Size.W = math.Max(g.Size.W, dx)
+	g.Size.H = math.Max(g.Size.H, g.Size.H + g.Gap() + dy)
+}
+
+func (verticalLayout) Place(g *Group, elem *Element, c *Bound) {
+	// 垂直步进，前进一个控件高度
+	_, dy := g.Extent(elem)
+	c.Y = math.Max(c.Y + dy + g.Spacing, 0)
+}
+
+type overlayLayout struct {}
+
+func (overlayLayout) Measure(g *Group, elem *Element) {
+	// 重叠, 取高或者宽的最大值
+	dx, dy := g.Extent(elem)
+	g.Size.W = math.Max(g.Size.W, dx)
+	g.Size.H = math.Max(g.Size.H, dy)
+}
+
+func (overlayLayout) Place(g *Group, elem *Element, c *Bound) {
+	// 保持原来的位置不变..
+}
+
+// FrameLayout: 所有子元素从同一个原点开始, 大小取最大的子元素,
+// 子元素在 EndLayout 时根据各自的 Gravity 摆放在 Group 中
+type frameLayout struct {
+	overlayLayout
+}
+
+func (frameLayout) Arrange(g *Group, children []*Element) {
+	cw, ch := g.Size.W, g.Size.H
+	if g.hasSize {
+		if w, h := g.Content(); w > 0 && h > 0 {
+			cw, ch = w, h
+		}
+	}
+	for _, c := range children {
+		var (
+			gravity = g.gravityOf(c)
+			dx, dy = g.Extent(c)
+		)
+		c.X = g.Padding.Left + (cw-dx)*gravity.X + c.Left
+		c.Y = g.Padding.Top + (ch-dy)*gravity.Y + c.Top
+	}
+}
+
+func init() {
+	RegisterLayout(LinearHorizontal, horizontalLayout{})
+	RegisterLayout(LinearVertical, verticalLayout{})
+	RegisterLayout(LinearOverLay, overlayLayout{})
+	RegisterLayout(GridLayout, gridLayout{})
+	RegisterLayout(FlowLayout, flowLayout{})
+	RegisterLayout(FrameLayout, frameLayout{})
+}
@@ -0,0 +1,59 @@
+package gui
+
+import (
+	"log"
+)
+
+type Axis int
+
+const (
+	AxisX Axis = iota
+	AxisY
+)
+
+type sizeMatch struct {
+	target ID
+	axis   Axis
+	valid  bool
+}
+
+// 下一个元素的宽度(AxisX)或者高度(AxisY)和 target 相同
+// target 必须在本帧已经摆放过(比如同一行前面的 label), 否则打印日志并忽略
+func (lyt *LayoutManager) MatchSize(target ID, axis Axis) *LayoutManager {
+	lyt.match = sizeMatch{target: target, axis: axis, valid: true}
+	return lyt
+}
+
+// 把 MatchSize 应用到 elem 上, 返回是否设置了大小
+func (lyt *LayoutManager) applyMatch(elem *Element) bool {
+	m := lyt.match
+	if !m.valid {
+		return false
+	}
+	lyt.match.valid = false
+
+	target, ok := lyt.placed(m.target)
+	if !ok {
+		log.Println("gui: MatchSize target", m.target, "of element", elem.id, "is not placed yet")
+		return false
+	}
+	switch m.axis {
+	case AxisX:
+		elem.W = target.W
+	case AxisY:
+		elem.H = target.H
+	}
+	return true
+}
+
+// 本帧已经摆放过的元素: EndElement/EndLayout 记录的 declaredIn 不早于本帧的根布局,
+// 所以已经结束的兄弟 Group 中的元素也能找到
+func (lyt *LayoutManager) placed(id ID) (elem *Element, ok bool) {
+	if len(lyt.groupStack) == 0 {
+		return nil, false
+	}
+	if elem, ok = lyt.Element(id); ok {
+		ok = elem.declaredIn != 0 && elem.declaredIn >= lyt.groupStack[0].serial
+	}
+	return
+}
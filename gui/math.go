@@ -3,10 +3,26 @@ package gui
 import (
 	"github.com/go-gl/mathgl/mgl32"
 	"math"
+	"log"
 )
 
 const PI float32 = 3.14
 
+var nanLogged bool
+
+// 布局的输入是 NaN/Inf 时按 0 处理, 避免一个错误的值污染整个布局
+// 只打印一次日志
+func finite(v float32) float32 {
+	if f := float64(v); math.IsNaN(f) || math.IsInf(f, 0) {
+		if !nanLogged {
+			log.Println("gui: NaN/Inf in layout input, treated as 0")
+			nanLogged = true
+		}
+		return 0
+	}
+	return v
+}
+
 func InvLength(v mgl32.Vec2, fail float32) float32 {
 	return 1/float32(math.Sqrt(float64(v[0] * v[0] + v[1] * v[1])))
 }
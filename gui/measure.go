@@ -0,0 +1,214 @@
+package gui
+
+import (
+	"korok.io/korok/engi/math"
+
+	"log"
+)
+
+// 测量元素内容的大小
+// maxW, maxH 是当前 Group 留给元素的空间，0 表示不限制
+// 返回内容完整显示需要的大小(比如不折行的文字宽度)
+type MeasureFunc func(maxW, maxH float32) (w, h float32)
+
+// 设置元素的测量函数，设置后 BeginElement 会用它来计算元素的大小
+func (lyt *LayoutManager) SetMeasure(id ID, fn MeasureFunc) {
+	lyt.obtain(id).measure = fn
+}
+
+// 内容是否被截断，渲染时可以用来绘制省略号
+func (lyt *LayoutManager) Truncated(id ID) bool {
+	if elem, ok := lyt.Element(id); ok {
+		return elem.truncated
+	}
+	return false
+}
+
+// sized 为 true 时表示元素本帧设置了大小, 否则使用测量的大小
+func (lyt *LayoutManager) measure(elem *Element, sized bool) {
+	var (
+		g = lyt.hGroup
+		maxW, maxH float32
+	)
+	if g.hasSize {
+		maxW = g.W - g.Padding.Right - elem.X - elem.Right
+		maxH = g.H - g.Padding.Bottom - elem.Y - elem.Bottom
+	}
+	w, h := elem.measure(maxW, maxH)
+	w, h = finite(w), finite(h)
+	elem.sized = sized
+	if !sized {
+		elem.W, elem.H = w, h
+	}
+	if maxW > 0 && elem.W > maxW {
+		elem.W = maxW
+	}
+	elem.truncated = elem.W < w
+}
+
+// WrapContent(W = 0, H = 0) 的元素没有测量函数, 或者测量函数返回 0 时使用的大小,
+// 方便在开发时看到还没有实现测量的元素. 默认为 0, 即不显示
+// 设置了 Property.Weight 的元素由剩余空间决定大小, 不使用这个值
+func (lyt *LayoutManager) SetDefaultContentSize(w, h float32) {
+	lyt.defaultContent.W, lyt.defaultContent.H = math.Max(finite(w), 0), math.Max(finite(h), 0)
+}
+
+func (lyt *LayoutManager) applyDefaultContent(elem *Element) {
+	if elem.W == 0 && elem.H == 0 && elem.weight == 0 {
+		elem.W, elem.H = lyt.defaultContent.W, lyt.defaultContent.H
+	}
+}
+
+// 第二遍测量: 线性布局的交叉轴大小(水平布局的行高, 垂直布局的列宽)在所有子元素
+// 测量完之后才知道, 用它作为约束重新测量子元素, 这样子元素可以填满整行
+// 主轴方向的大小保持不变
+func (lyt *LayoutManager) measureCross(g *Group) {
+	horizontal := g.LayoutType == LinearHorizontal
+	if !horizontal && g.LayoutType != LinearVertical {
+		return
+	}
+	cw, ch := g.Size.W, g.Size.H
+	if g.hasSize {
+		if w, h := g.Content(); horizontal && h > 0 {
+			ch = h
+		} else if !horizontal && w > 0 {
+			cw = w
+		}
+	}
+	for _, ii := range g.children {
+		c := &lyt.uiElements[ii]
+		if c.measure == nil || c.group || c.sized {
+			continue
+		}
+		// 只修改交叉轴的大小, 位置按 Gravity(或锚点)平移大小的变化量,
+		// 保留声明时的偏移(锚点, 光标偏移等)
+		if horizontal {
+			maxH := ch - c.Top - c.Bottom
+			_, h := c.measure(c.W, maxH)
+			h = finite(h)
+			old := c.H
+			c.H = math.Min(h, maxH)
+			c.truncated = c.H < h
+			c.Y += (old - c.H) * crossAlign(g, c, false)
+		} else {
+			maxW := cw - c.Left - c.Right
+			w, _ := c.measure(maxW, c.H)
+			w = finite(w)
+			old := c.W
+			c.W = math.Min(w, maxW)
+			c.truncated = c.W < w
+			c.X += (old - c.W) * crossAlign(g, c, true)
+		}
+	}
+}
+
+// 元素大小变化时保持不动的点: 0 是左/上边, 1 是右/下边
+func crossAlign(g *Group, c *Element, horizontal bool) float32 {
+	a, gravity := c.anchorV, g.gravityOf(c).Y
+	if horizontal {
+		a, gravity = c.anchorH, g.gravityOf(c).X
+	}
+	if !a.valid {
+		return gravity
+	}
+	if a.edge == EdgeRight || a.edge == EdgeBottom {
+		return 1
+	}
+	return 0
+}
+
+// 宽高互相依赖的迭代次数上限, 达到上限时使用最后一次的结果
+const maxCoupledPasses = 8
+
+// 收敛的精度, 单位是布局单位
+const coupledEpsilon = .5
+
+// 标记元素的宽和高互相依赖(比如折行的文字: 高度取决于宽度, 而 Group 的宽度又取决于它),
+// EndLayout 时会反复测量, 直到大小不再变化, 最多 maxCoupledPasses 次
+func (lyt *LayoutManager) SetCoupled(id ID, coupled bool) {
+	lyt.obtain(id).coupled = coupled
+}
+
+// 线性布局中 coupled 元素的不动点迭代:
+// 用 Group 交叉轴的大小测量元素, 元素的大小又决定了 Group 的大小
+func (lyt *LayoutManager) solveCoupled(g *Group) {
+	horizontal := g.LayoutType == LinearHorizontal
+	if !horizontal && g.LayoutType != LinearVertical {
+		return
+	}
+	var coupled []*Element
+	for _, ii := range g.children {
+		if c := &lyt.uiElements[ii]; c.coupled && c.measure != nil && !c.group && !c.sized {
+			coupled = append(coupled, c)
+		}
+	}
+	if len(coupled) == 0 {
+		return
+	}
+	pass, changed := 0, true
+	for ; changed && pass < maxCoupledPasses; pass++ {
+		changed = false
+		cross := lyt.crossSize(g, horizontal)
+		for _, c := range coupled {
+			var w, h float32
+			if horizontal {
+				maxH := cross - c.Top - c.Bottom
+				w, h = c.measure(0, maxH)
+				w, h = finite(w), math.Min(finite(h), maxH)
+				g.Size.W += w - c.W
+			} else {
+				maxW := cross - c.Left - c.Right
+				w, h = c.measure(maxW, 0)
+				w, h = math.Min(finite(w), maxW), finite(h)
+				g.Size.H += h - c.H
+			}
+			if !near(w, c.W) || !near(h, c.H) {
+				changed = true
+			}
+			c.W, c.H = w, h
+		}
+		if !g.hasSize {
+			if horizontal {
+				g.Size.H = lyt.crossSize(g, horizontal)
+			} else {
+				g.Size.W = lyt.crossSize(g, horizontal)
+			}
+		}
+	}
+	lyt.coupledPasses, lyt.coupledConverged = pass, !changed
+	if changed {
+		log.Println("gui: coupled layout of group", g.id, "did not converge in", pass, "passes")
+	}
+	lyt.arrange(g, restack)
+}
+
+// 最近一次 coupled 元素迭代的次数(见 SetCoupled)和是否收敛,
+// 正好在第 maxCoupledPasses 次收敛时 converged 也是 true. 没有收敛时会输出一条日志
+func (lyt *LayoutManager) LastConvergenceIterations() (passes int, converged bool) {
+	return lyt.coupledPasses, lyt.coupledConverged
+}
+
+// 线性布局交叉轴的大小: 设置了大小时是内容区域, 否则是子元素的最大值
+func (lyt *LayoutManager) crossSize(g *Group, horizontal bool) (cross float32) {
+	if cw, ch := g.Content(); g.hasSize {
+		if horizontal && ch > 0 {
+			return ch
+		} else if !horizontal && cw > 0 {
+			return cw
+		}
+	}
+	for _, ii := range g.children {
+		dx, dy := g.Extent(&lyt.uiElements[ii])
+		if horizontal {
+			cross = math.Max(cross, dy)
+		} else {
+			cross = math.Max(cross, dx)
+		}
+	}
+	return
+}
+
+func near(a, b float32) bool {
+	d := a - b
+	return d <= coupledEpsilon && d >= -coupledEpsilon
+}
@@ -0,0 +1,51 @@
+package gui
+
+// MeasureOnly 使用的临时 Group
+const measureID ID = -2
+
+// 只测量 fn 的大小, 不保留 fn 对布局的任何修改
+// fn 在一个临时的 LinearOverLay 中执行, 所以测量结果是 fn 中最大的元素(一般是一个完整的 Group)
+func (lyt *LayoutManager) MeasureOnly(fn func()) (w, h float32) {
+	var (
+		elements = lyt.uiElements
+		backup = append([]Element(nil), elements...)
+		stack = lyt.groupStack
+		group = *lyt.hGroup
+		cursor = lyt.Cursor
+		clips = len(lyt.clips)
+		autoSeq, serial, match = lyt.autoSeq, lyt.groupSerial, lyt.match
+	)
+
+	lyt.PushLayout(LinearOverLay, lyt.NewElement(measureID))
+	fn()
+	size := lyt.hGroup.Size
+	lyt.EndLayout()
+	w, h = size.W, size.H
+
+	// 恢复, 打开的 Group 指向原来的数组, 所以要写回原来的数组
+	for k := len(backup); k < len(lyt.uiElements); k++ {
+		if id := lyt.uiElements[k].id; lyt.ids[id] == k {
+			delete(lyt.ids, id)
+		}
+	}
+	copy(elements, backup)
+	lyt.uiElements = elements[:len(backup)]
+	lyt.groupStack = stack
+	lyt.hGroup = &stack[len(stack)-1]
+	*lyt.hGroup = group
+	lyt.Cursor = cursor
+	lyt.clips = lyt.clips[:clips]
+	// fn 中的 AutoElement 和 PushLayout 不影响之后的 ID 和编号
+	lyt.autoSeq, lyt.groupSerial, lyt.match = autoSeq, serial, match
+	return
+}
+
+// 先测量 full, 如果放得下(available 的 W/H 为 0 表示不限制)就布局 full, 否则布局 compact
+func (lyt *LayoutManager) IfFits(available Bound, full func(), compact func()) {
+	w, h := lyt.MeasureOnly(full)
+	if (available.W == 0 || w <= available.W) && (available.H == 0 || h <= available.H) {
+		full()
+	} else {
+		compact()
+	}
+}
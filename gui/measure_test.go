@@ -0,0 +1,302 @@
+package gui
+
+import (
+	"testing"
+
+	"korok.io/korok/engi/math"
+)
+
+func TestTruncated(t *testing.T) {
+	lyt := New()
+
+	label := func(maxW, maxH float32) (w, h float32) {
+		return 120, 12
+	}
+	lyt.SetMeasure(2, label)
+	lyt.SetMeasure(3, label)
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetSize(50, 100)
+		elem, _ := lyt.BeginElement(2)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+
+		// no constraint in the default group
+		elem, _ = lyt.BeginElement(3)
+		lyt.EndElement(elem)
+	}
+	frame()
+	frame()
+
+	if !lyt.Truncated(2) {
+		t.Error("wide label in narrow group should be truncated")
+	}
+	if elem, _ := lyt.Element(2); elem.W != 50 {
+		t.Error("truncated label width:", elem.W)
+	}
+	if lyt.Truncated(3) {
+		t.Error("label without constraint should not be truncated")
+	}
+}
+
+func TestIfFits(t *testing.T) {
+	lyt := New()
+
+	var chosen string
+	row := func(id ID, xtype LayoutType, name string) func() {
+		return func() {
+			chosen = name
+			lyt.PushLayout(xtype, layoutOf(lyt, id))
+			for i := ID(1); i <= 3; i++ {
+				elem, _ := lyt.BeginElement(id + i)
+				elem.Size(50, 20)
+				lyt.EndElement(elem)
+			}
+			lyt.EndLayout()
+		}
+	}
+	full := row(10, LinearHorizontal, "full")
+	compact := row(20, LinearVertical, "compact")
+
+	lyt.IfFits(Bound{W: 100}, full, compact)
+	if chosen != "compact" {
+		t.Error("full layout overflows, compact should be chosen")
+	}
+	if _, ok := lyt.Element(11); ok {
+		t.Error("measure pass should not leave elements behind")
+	}
+
+	lyt.IfFits(Bound{W: 200}, full, compact)
+	if chosen != "full" {
+		t.Error("full layout fits, it should be chosen")
+	}
+	if _, ok := lyt.Element(11); !ok {
+		t.Error("committed layout should create elements")
+	}
+}
+
+func TestIfFitsAutoID(t *testing.T) {
+	lyt := New()
+
+	var ids []ID
+	full := func() {
+		id, elem, _ := lyt.AutoElement()
+		elem.Size(50, 20)
+		lyt.EndElement(elem)
+		ids = append(ids, id)
+	}
+	frame := func() {
+		lyt.BeginFrame()
+		lyt.IfFits(Bound{W: 100}, full, nil)
+		lyt.EndFrame()
+	}
+	frame()
+	frame()
+
+	// the measure pass must not consume auto ids, so every frame gets the same one
+	for _, id := range ids {
+		if id != AutoIDBase {
+			t.Error("auto id should be stable across frames:", ids)
+			break
+		}
+	}
+}
+
+func TestMeasureCrossAxis(t *testing.T) {
+	lyt := New()
+
+	// the card fills whatever height it's given
+	card := func(maxW, maxH float32) (w, h float32) {
+		return 40, math.Max(maxH, 20)
+	}
+	lyt.SetMeasure(2, card)
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		elem, _ := lyt.BeginElement(2)
+		lyt.EndElement(elem)
+		lyt.Cursor.SetSize(40, 60).To(3)
+		elem, _ = lyt.BeginElement(3)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	if elem, _ := lyt.Element(2); elem.H != 60 || elem.W != 40 {
+		t.Error("card should fill the row height:", elem.Bound)
+	}
+	if g, _ := lyt.Element(1); g.H != 60 {
+		t.Error("row height:", g.H)
+	}
+}
+
+func TestMeasureCrossKeepsOffsets(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	// 比行高更高的内容
+	lyt.SetMeasure(2, func(maxW, maxH float32) (w, h float32) {
+		return 40, 80
+	})
+	// 交叉轴上只占一半
+	lyt.SetMeasure(3, func(maxW, maxH float32) (w, h float32) {
+		return 20, maxH / 2
+	})
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		lyt.SetSize(100, 60)
+		elem, _ := lyt.BeginElement(2)
+		lyt.EndElement(elem)
+		lyt.Cursor.AnchorBottom(0).To(3)
+		elem, _ = lyt.BeginElement(3)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	if elem, _ := lyt.Element(2); elem.H != 60 || !lyt.Truncated(2) {
+		t.Error("row child taller than the row should be truncated:", elem.Bound, lyt.Truncated(2))
+	}
+	if elem, _ := lyt.Element(3); elem.H != 30 || elem.Y+elem.H != 60 {
+		t.Error("anchored child should stay on the bottom edge:", elem.Bound)
+	}
+}
+
+func TestCoupledMeasure(t *testing.T) {
+	lyt := New()
+
+	// a label that gets narrower when it's given less room,
+	// converging to w = 40
+	label := func(maxW, maxH float32) (w, h float32) {
+		if maxW <= 0 {
+			return 100, 10
+		}
+		return maxW/4 + 30, 10
+	}
+	lyt.SetMeasure(2, label)
+	lyt.SetCoupled(2, true)
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		elem, _ := lyt.BeginElement(2)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	if lyt.coupledPasses > maxCoupledPasses {
+		t.Error("iteration should stop at the cap:", lyt.coupledPasses)
+	}
+	elem, _ := lyt.Element(2)
+	if w, _ := label(elem.W, 0); !near(w, elem.W) || !near(elem.W, 40) {
+		t.Error("label should converge to a fixed point:", elem.W, w)
+	}
+	if g, _ := lyt.Element(1); g.W != elem.W {
+		t.Error("group width should follow the label:", g.W, elem.W)
+	}
+}
+
+func TestDefaultContentSize(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+	lyt.SetDefaultContentSize(40, 16)
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		for id := ID(2); id <= 3; id++ {
+			elem, _ := lyt.BeginElement(id)
+			lyt.EndElement(elem)
+		}
+		lyt.Cursor.SetSize(10, 10).To(4)
+		elem, _ := lyt.BeginElement(4)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	for _, id := range []ID{2, 3} {
+		if e, _ := lyt.Element(id); e.W != 40 || e.H != 16 {
+			t.Error("wrap-content element", id, "should use the default size:", e.Bound)
+		}
+	}
+	if e, _ := lyt.Element(4); e.W != 10 || e.H != 10 {
+		t.Error("explicit size should win:", e.Bound)
+	}
+	if g, _ := lyt.Element(1); g.H != 42 {
+		t.Error("group should include the default sizes:", g.Bound)
+	}
+}
+
+func TestConvergenceIterations(t *testing.T) {
+	lyt := New()
+
+	// converges to w = 40: measureCross takes 100 -> 70, then
+	// 55 -> 47.5 -> 43.75 -> 41.88 -> 40.94 -> 40.47, the last step is within coupledEpsilon
+	label := func(maxW, maxH float32) (w, h float32) {
+		if maxW <= 0 {
+			return 100, 10
+		}
+		return maxW/2 + 20, 10
+	}
+	lyt.SetMeasure(2, label)
+	lyt.SetCoupled(2, true)
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		elem, _ := lyt.BeginElement(2)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+	if n, ok := lyt.LastConvergenceIterations(); n != 6 || !ok {
+		t.Error("coupled label should converge in 6 passes:", n, ok)
+	}
+
+	// 每次都比给出的宽度窄 1, 永远不会收敛
+	lyt.SetMeasure(2, func(maxW, maxH float32) (w, h float32) {
+		if maxW <= 0 {
+			return 100, 10
+		}
+		return maxW - 1, 10
+	})
+	frame()
+	if n, ok := lyt.LastConvergenceIterations(); n != maxCoupledPasses || ok {
+		t.Error("oscillating layout should hit the cap:", n, ok)
+	}
+
+	// 高度变化 maxCoupledPasses-1 次, 正好在最后一次迭代时收敛
+	var (
+		calls int
+		ending bool
+	)
+	lyt.SetMeasure(2, func(maxW, maxH float32) (w, h float32) {
+		if !ending {
+			return 40, 10
+		}
+		calls++
+		return 40, 10 + math.Min(float32(calls), maxCoupledPasses)
+	})
+	frame()
+	lyt.Move(0, 0)
+	lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+	elem, _ := lyt.BeginElement(2)
+	lyt.EndElement(elem)
+	ending = true
+	lyt.EndLayout()
+	if n, ok := lyt.LastConvergenceIterations(); n != maxCoupledPasses || !ok {
+		t.Error("layout converging on the last pass should report it:", n, ok)
+	}
+}
@@ -0,0 +1,76 @@
+package gui
+
+import "korok.io/korok/engi/math"
+
+// ResolveOverlaps 最多迭代的次数, 和推开时额外留出的距离(避免浮点误差留下很小的重叠)
+const (
+	maxResolvePasses = 32
+	resolveSlop float32 = .01
+)
+
+// 把互相重叠的元素(比如图表上的标签)推开, 并限制在 bounds(绝对坐标)之内
+// 每次迭代把重叠的两个元素沿重叠较少的方向各推开一半(一边碰到 bounds 时另一边推得更多),
+// 最多迭代 maxResolvePasses 次
+// 返回是否已经没有重叠(bounds 放不下所有元素时为 false)
+func (lyt *LayoutManager) ResolveOverlaps(ids []ID, bounds Bound) (resolved bool) {
+	var (
+		elems []*Element
+		rects []Bound
+	)
+	for _, id := range ids {
+		if elem, ok := lyt.Element(id); ok {
+			elems, rects = append(elems, elem), append(rects, lyt.absBound(elem))
+		}
+	}
+	for pass := 0; pass < maxResolvePasses && !resolved; pass++ {
+		resolved = true
+		for i := range rects {
+			for j := i + 1; j < len(rects); j++ {
+				a, b := &rects[i], &rects[j]
+				o := a.Intersect(*b)
+				if o.Empty() {
+					continue
+				}
+				resolved = false
+				// 中心在前面的往前推, 重合时按顺序
+				lo, hi := bounds.X, bounds.X+bounds.W
+				switch {
+				case o.W < o.H && a.X+a.W/2 <= b.X+b.W/2:
+					separate(&a.X, &b.X, b.W, o.W, lo, hi)
+				case o.W < o.H:
+					separate(&b.X, &a.X, a.W, o.W, lo, hi)
+				case a.Y+a.H/2 <= b.Y+b.H/2:
+					separate(&a.Y, &b.Y, b.H, o.H, bounds.Y, bounds.Y+bounds.H)
+				default:
+					separate(&b.Y, &a.Y, a.H, o.H, bounds.Y, bounds.Y+bounds.H)
+				}
+			}
+		}
+		for i := range rects {
+			rects[i] = clampBound(rects[i], bounds)
+		}
+	}
+	for i, elem := range elems {
+		old := lyt.absBound(elem)
+		dx, dy := rects[i].X-old.X, rects[i].Y-old.Y
+		elem.X, elem.Y = elem.X+dx, elem.Y+dy
+		if elem.group {
+			lyt.shiftGroup(elem.id, dx, dy)
+		}
+	}
+	return
+}
+
+// 一维的推开: a 向前, b 向后(b 的长度为 bw), 总共推开 overlap,
+// 尽量各推一半, 一边碰到 [lo, hi] 的边界时另一边多推一些
+func separate(a, b *float32, bw, overlap, lo, hi float32) {
+	overlap += resolveSlop
+	var (
+		roomA = math.Max(*a-lo, 0)
+		roomB = math.Max(hi-(*b+bw), 0)
+		da = math.Min(overlap/2, roomA)
+		db = math.Min(overlap-da, roomB)
+	)
+	da = math.Min(overlap-db, roomA)
+	*a, *b = *a-da, *b+db
+}
@@ -0,0 +1,32 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestResolveOverlaps(t *testing.T) {
+	lyt := New()
+	bounds := Bound{0, 0, 200, 100}
+	labels := []ID{2, 3, 4}
+	lyt.PlaceAt(2, mgl32.Vec2{0, 10}, Bound{W: 40, H: 20})
+	lyt.PlaceAt(3, mgl32.Vec2{10, 15}, Bound{W: 40, H: 20})
+	lyt.PlaceAt(4, mgl32.Vec2{20, 12}, Bound{W: 40, H: 20})
+
+	if !lyt.ResolveOverlaps(labels, bounds) {
+		t.Error("overlaps should be resolved")
+	}
+	for i, a := range labels {
+		ba, _ := lyt.BoundsOf(a)
+		if ba.X < 0 || ba.Y < 0 || ba.X+ba.W > 200 || ba.Y+ba.H > 100 {
+			t.Error("label", a, "should stay within bounds:", ba)
+		}
+		for _, b := range labels[i+1:] {
+			bb, _ := lyt.BoundsOf(b)
+			if !ba.Intersect(bb).Empty() {
+				t.Error("labels", a, b, "still overlap:", ba, bb)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package gui
+
+import (
+	geo "math"
+)
+
+// 像素风格的 UI 使用虚拟分辨率, 所有元素的位置和大小都对齐到 cell 的整数倍,
+// spacing 和 padding 也会对齐. 和 SetPixelSnap 不同, 这里影响布局计算本身.
+// cell = 0 关闭对齐, 需要在布局之前设置
+func (lyt *LayoutManager) SetGrid(cell float32) {
+	lyt.gridCell = cell
+}
+
+// 位置取最近的网格线
+func (lyt *LayoutManager) toGrid(v float32) float32 {
+	c := lyt.gridCell
+	return float32(geo.Floor(float64(v/c)+.5)) * c
+}
+
+// 大小向上取整, 保证内容放得下
+func (lyt *LayoutManager) ceilGrid(v float32) float32 {
+	c := lyt.gridCell
+	return float32(geo.Ceil(float64(v/c))) * c
+}
+
+func (lyt *LayoutManager) snapToGrid(elem *Element) {
+	m := &elem.Margin
+	m.Left, m.Right, m.Top, m.Bottom = lyt.toGrid(m.Left), lyt.toGrid(m.Right), lyt.toGrid(m.Top), lyt.toGrid(m.Bottom)
+	elem.X, elem.Y = lyt.toGrid(elem.X), lyt.toGrid(elem.Y)
+	elem.W, elem.H = lyt.ceilGrid(elem.W), lyt.ceilGrid(elem.H)
+}
+
+// Arranger(比如表格)在 EndLayout 时重新摆放了子元素, 再对齐一次
+func (lyt *LayoutManager) snapChildren(g *Group) {
+	if _, ok := layouters[g.LayoutType].(Arranger); !ok {
+		return
+	}
+	for _, ii := range g.children {
+		if c := &lyt.uiElements[ii]; !c.group {
+			lyt.snapToGrid(c)
+		}
+	}
+}
+
+// 元素大小的取整方式
+// RoundNone:    不取整(默认)
+// RoundNearest: 四舍五入
+// RoundUp:      向上取整
+type RoundMode uint8
+
+const (
+	RoundNone RoundMode = iota
+	RoundNearest
+	RoundUp
+)
+
+// 在 Extend/Advance 时把元素的大小和 margin 取整, 这样光标总是落在整数上,
+// 相邻的元素首尾相接, 不会因为浮点数累加出现缝隙或者重叠
+func (lyt *LayoutManager) SetRoundMode(mode RoundMode) {
+	lyt.roundMode = mode
+}
+
+func (lyt *LayoutManager) roundElem(elem *Element) {
+	var round func(float64) float64
+	switch lyt.roundMode {
+	case RoundNearest:
+		round = func(v float64) float64 { return geo.Floor(v + .5) }
+	case RoundUp:
+		round = geo.Ceil
+	default:
+		return
+	}
+	r := func(v *float32) {
+		*v = float32(round(float64(*v)))
+	}
+	r(&elem.W); r(&elem.H)
+	r(&elem.Left); r(&elem.Right); r(&elem.Top); r(&elem.Bottom)
+}
+
+// 垂直布局中每个子元素的顶部向下对齐到 px 的整数倍(相对于 Group), 保持统一的垂直节奏,
+// 后面的元素跟着向下移动, Group 的高度也随之增加. px = 0 关闭对齐
+func (lyt *LayoutManager) SetBaselineGrid(px float32) *LayoutManager {
+	lyt.hGroup.baselineGrid = finite(px)
+	return lyt
+}
+
+func (lyt *LayoutManager) applyBaselineGrid(g *Group) {
+	px := g.baselineGrid
+	if px <= 0 || g.LayoutType != LinearVertical {
+		return
+	}
+	var shift float32
+	lyt.arrange(g, func(g *Group, children []*Element) {
+		for _, c := range children {
+			y := float32(geo.Ceil(float64((c.Y+shift)/px))) * px
+			shift, c.Y = y-c.Y, y
+		}
+	})
+	g.Size.H += shift
+}
@@ -0,0 +1,92 @@
+package gui
+
+import (
+	"testing"
+)
+
+func TestSetGrid(t *testing.T) {
+	lyt := New()
+	lyt.SetGrid(8)
+
+	frame := func() {
+		lyt.Move(3, 3)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetPadding(5, 5, 5, 5).SetGravity(.5, 0)
+		for id := ID(2); id <= 4; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(float32(10*id), 10)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	onGrid := func(v float32) bool {
+		return v == float32(int(v/8))*8
+	}
+	lyt.Visit(func(info ElementInfo) {
+		b := info.Bound
+		if !onGrid(b.X) || !onGrid(b.Y) || !onGrid(b.W) || !onGrid(b.H) {
+			t.Error("element", info.ID, "is not on the 8px grid:", b)
+		}
+	})
+}
+
+func TestRoundMode(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+	lyt.SetRoundMode(RoundNearest)
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		for id := ID(2); id < 12; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(10.3, 10)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	for id := ID(2); id < 11; id++ {
+		a, _ := lyt.Element(id)
+		b, _ := lyt.Element(id + 1)
+		if a.X+a.W != b.X {
+			t.Error("seam between", id, id+1, ":", a.Bound, b.Bound)
+		}
+	}
+	if g, _ := lyt.Element(1); g.W != 100 {
+		t.Error("no cumulative drift expected:", g.W)
+	}
+}
+
+func TestBaselineGrid(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetBaselineGrid(4)
+		for i, h := range []float32{10, 15, 5} {
+			elem, _ := lyt.BeginElement(ID(2 + i))
+			elem.Size(20, h)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	// 0 -> 0, 10 -> 12, 12+15 = 27 -> 28
+	for i, y := range []float32{0, 12, 28} {
+		if e, _ := lyt.Element(ID(2 + i)); e.Y != y {
+			t.Error("child", 2+i, "should snap to", y, ":", e.Y)
+		}
+	}
+	if g, _ := lyt.Element(1); g.H != 33 {
+		t.Error("group should grow with the snapped children:", g.H)
+	}
+}
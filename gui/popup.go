@@ -0,0 +1,121 @@
+package gui
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"korok.io/korok/engi/math"
+)
+
+// 鼠标指针周围不放提示框的范围(半径), 大约是指针图标的大小
+const tooltipGap float32 = 16
+
+// 弹出框(下拉框/提示框)的摆放，相对于一个锚点元素
+// preferred 表示弹出方向:
+// Left2Right - 锚点右边
+// Right2Left - 锚点左边
+// Top2Bottom - 锚点下边
+// Bottom2Top - 锚点上边
+// 如果这一边放不下，就翻转到另一边，最后限制在 screen 之内.
+// 返回弹出框的绝对坐标
+func (lyt *LayoutManager) PlacePopup(anchor ID, popupSize Bound, preferred Direction, screen Bound) (b Bound) {
+	b.W, b.H = popupSize.W, popupSize.H
+
+	elem, ok := lyt.Element(anchor)
+	if !ok {
+		return
+	}
+	a := lyt.absBound(elem)
+
+	switch preferred {
+	case Left2Right, Right2Left:
+		var (
+			right = a.X + a.W
+			left  = a.X - b.W
+			fitR  = right+b.W <= screen.X+screen.W
+			fitL  = left >= screen.X
+		)
+		if (preferred == Left2Right && (fitR || !fitL)) || (preferred == Right2Left && !fitL && fitR) {
+			b.X = right
+		} else {
+			b.X = left
+		}
+		b.Y = a.Y
+	case Top2Bottom, Bottom2Top:
+		var (
+			below  = a.Y + a.H
+			above  = a.Y - b.H
+			fitB   = below+b.H <= screen.Y+screen.H
+			fitA   = above >= screen.Y
+		)
+		if (preferred == Top2Bottom && (fitB || !fitA)) || (preferred == Bottom2Top && !fitA && fitB) {
+			b.Y = below
+		} else {
+			b.Y = above
+		}
+		b.X = a.X
+	}
+	return clampBound(b, screen)
+}
+
+// 跟随鼠标的提示框, 默认放在指针的右下方, 和指针之间隔开 tooltipGap,
+// 某一边超出 screen 时翻转到指针的另一边, 然后限制在 screen 之内.
+// 水平和垂直方向都放不下时放在空间最大的一边, 只在另一个方向上限制在 screen 之内,
+// 这时提示框会超出 screen, 但不会盖住指针.
+// 返回提示框的绝对坐标
+func PlaceTooltip(pointer mgl32.Vec2, size Bound, screen Bound) (b Bound) {
+	var (
+		right = pointer[0] + tooltipGap
+		left  = pointer[0] - tooltipGap - size.W
+		below = pointer[1] + tooltipGap
+		above = pointer[1] - tooltipGap - size.H
+	)
+	b.W, b.H = size.W, size.H
+	b.X = right
+	if b.X+b.W > screen.X+screen.W && left >= screen.X {
+		b.X = left
+	}
+	b.Y = below
+	if b.Y+b.H > screen.Y+screen.H && above >= screen.Y {
+		b.Y = above
+	}
+	fitX := b.X >= screen.X && b.X+b.W <= screen.X+screen.W
+	fitY := b.Y >= screen.Y && b.Y+b.H <= screen.Y+screen.H
+	if fitX || fitY {
+		// 有一个方向和指针分开, 另一个方向随意移动都不会盖住指针
+		return clampBound(b, screen)
+	}
+
+	// 指针四边的空间
+	rooms := [4]float32{
+		screen.X + screen.W - right,
+		pointer[0] - tooltipGap - screen.X,
+		screen.Y + screen.H - below,
+		pointer[1] - tooltipGap - screen.Y,
+	}
+	side := 0
+	for i := range rooms {
+		if rooms[i] > rooms[side] {
+			side = i
+		}
+	}
+	c := clampBound(b, screen)
+	switch side {
+	case 0, 1:
+		b.X, b.Y = right, c.Y
+		if side == 1 {
+			b.X = left
+		}
+	default:
+		b.X, b.Y = c.X, below
+		if side == 3 {
+			b.Y = above
+		}
+	}
+	return
+}
+
+// 把 b 移动到 screen 之内, 大于 screen 的时候对齐左上角
+func clampBound(b, screen Bound) Bound {
+	b.X = math.Max(math.Min(b.X, screen.X+screen.W-b.W), screen.X)
+	b.Y = math.Max(math.Min(b.Y, screen.Y+screen.H-b.H), screen.Y)
+	return b
+}
@@ -0,0 +1,89 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+func TestPlacePopup(t *testing.T) {
+	lyt := New()
+
+	var (
+		screen = Bound{0, 0, 480, 320}
+		popup  = Bound{W: 100, H: 50}
+	)
+	anchor := func(id ID, b Bound) {
+		elem := lyt.NewElement(id)
+		elem.parent = 0
+		elem.Bound = b
+	}
+	anchor(1, Bound{200, 300, 40, 20}) // bottom edge
+	anchor(2, Bound{200, 0, 40, 20})   // top edge
+	anchor(3, Bound{440, 100, 40, 20}) // right edge
+	anchor(4, Bound{0, 100, 40, 20})   // left edge
+
+	cases := []struct {
+		id        ID
+		preferred Direction
+		expect    Bound
+	}{
+		{1, Top2Bottom, Bound{200, 250, 100, 50}},
+		{2, Bottom2Top, Bound{200, 20, 100, 50}},
+		{3, Left2Right, Bound{340, 100, 100, 50}},
+		{4, Right2Left, Bound{40, 100, 100, 50}},
+		// no flip, but clamp the cross axis
+		{3, Top2Bottom, Bound{380, 120, 100, 50}},
+	}
+	for _, c := range cases {
+		if b := lyt.PlacePopup(c.id, popup, c.preferred, screen); b != c.expect {
+			t.Error("popup of", c.id, "placed at:", b, "expected:", c.expect)
+		}
+	}
+}
+
+func TestPlaceTooltip(t *testing.T) {
+	var (
+		screen = Bound{0, 0, 480, 320}
+		tip    = Bound{W: 100, H: 40}
+	)
+	cases := []struct {
+		pointer mgl32.Vec2
+		expect  Bound
+	}{
+		{mgl32.Vec2{10, 10}, Bound{26, 26, 100, 40}},    // top-left: below-right
+		{mgl32.Vec2{470, 10}, Bound{354, 26, 100, 40}},  // top-right: flip left
+		{mgl32.Vec2{10, 310}, Bound{26, 254, 100, 40}},  // bottom-left: flip up
+		{mgl32.Vec2{470, 310}, Bound{354, 254, 100, 40}}, // bottom-right: flip both
+	}
+	// 比每一边的空间都大: 放在空间最大的一边, 只在另一个方向上限制
+	huge := []struct {
+		pointer mgl32.Vec2
+		expect  Bound
+	}{
+		{mgl32.Vec2{200, 150}, Bound{216, 120, 300, 200}}, // right
+		{mgl32.Vec2{280, 150}, Bound{-36, 120, 300, 200}}, // left
+		{mgl32.Vec2{240, 40}, Bound{180, 56, 300, 270}},    // below
+		{mgl32.Vec2{240, 280}, Bound{180, -6, 300, 270}},   // above
+	}
+	for _, c := range cases {
+		b := PlaceTooltip(c.pointer, tip, screen)
+		if b != c.expect {
+			t.Error("tooltip at", c.pointer, "placed at:", b, "expected:", c.expect)
+		}
+		gap := Bound{c.pointer[0] - tooltipGap, c.pointer[1] - tooltipGap, 2 * tooltipGap, 2 * tooltipGap}
+		if !b.Intersect(gap).Empty() {
+			t.Error("tooltip should not cover the pointer:", b)
+		}
+	}
+	for _, c := range huge {
+		b := PlaceTooltip(c.pointer, Bound{W: c.expect.W, H: c.expect.H}, screen)
+		if b != c.expect {
+			t.Error("large tooltip at", c.pointer, "placed at:", b, "expected:", c.expect)
+		}
+		gap := Bound{c.pointer[0] - tooltipGap, c.pointer[1] - tooltipGap, 2 * tooltipGap, 2 * tooltipGap}
+		if !b.Intersect(gap).Empty() {
+			t.Error("large tooltip should not cover the pointer:", b)
+		}
+	}
+}
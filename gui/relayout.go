@@ -0,0 +1,182 @@
+package gui
+
+// 只重新布局一个 Group, 比如只有一个面板的内容变化了
+// fn 声明 Group 的内容(和完整布局时 PushLayout 与 EndLayout 之间的代码一样),
+// Group 保持原来的位置, 大小变化后依次修正上层 Group 的大小,
+// 线性布局中排在它后面的兄弟元素整体平移, 其它子树不会重新布局.
+// SetSize 固定了大小的上层 Group 不再变化, fn 中没有再声明的旧元素被删除.
+// Group 必须在之前的帧中已经布局过
+// 在 BatchUpdate 中只记录下来, BatchUpdate 结束时才重新布局
+// fn 为 nil 时使用这个 Group 上一次的内容函数, 没有的话返回 false
+func (lyt *LayoutManager) RelayoutGroup(id ID, fn func()) bool {
+	elem, ok := lyt.Element(id)
+	if !ok || !elem.group || elem.parent < 0 {
+		return false
+	}
+	if lyt.batching {
+		lyt.markRelayout(id, fn)
+		return true
+	}
+	if fn == nil {
+		if fn = lyt.contents[id]; fn == nil {
+			return false
+		}
+	}
+	if lyt.contents == nil {
+		lyt.contents = make(map[ID]func())
+	}
+	lyt.contents[id] = fn
+	var (
+		old = elem.Bound
+		xtype = elem.layout
+		stack, hGroup, cursor = lyt.groupStack, lyt.hGroup, lyt.Cursor
+		clips, serial = lyt.clips, lyt.groupSerial
+		olds []ID
+	)
+	for i := range lyt.uiElements {
+		if e := &lyt.uiElements[i]; lyt.descendant(e.id, id) {
+			olds = append(olds, e.id)
+		}
+	}
+	// 保持 Group 原来的裁剪区域
+	lyt.clips = nil
+	if elem.clipped {
+		lyt.clips = []clipRect{{Bound: elem.clip, radius: elem.clipRadius, owner: elem.clipOwner, local: elem.clipLocal}}
+	}
+	// 一个临时的父容器, 使 Group 的位置保持不变
+	parent := &Element{id: elem.parent, Bound: Bound{X: old.X, Y: old.Y}, group: true}
+	lyt.groupStack = []Group{{LayoutType: LinearOverLay, Element: parent}}
+	lyt.hGroup = &lyt.groupStack[0]
+	lyt.Cursor = cursor
+	lyt.Cursor.X, lyt.Cursor.Y = 0, 0
+
+	lyt.PushLayout(xtype, elem)
+	fn()
+	lyt.EndLayout()
+
+	lyt.groupStack, lyt.hGroup, lyt.Cursor = stack, hGroup, cursor
+	lyt.clips = clips
+
+	// fn 没有再声明的旧元素
+	for _, old := range olds {
+		if e, ok := lyt.Element(old); ok && e.declaredIn <= serial {
+			lyt.dropElement(lyt.index(old))
+		}
+	}
+
+	// fn 中可能创建了新的元素, 重新查找
+	if elem, ok = lyt.Element(id); ok {
+		lyt.propagate(elem, old)
+	}
+	return true
+}
+
+type pendingRelayout struct {
+	id ID
+	fn func()
+}
+
+// 在 fn 中的 RelayoutGroup 和对已布局元素的 Set* 调用(SetProperty, SetMeasure 等)只标记 Group 需要重新布局,
+// fn 结束后每个 Group 只重新布局一次(使用最后一次传入的内容函数), 避免连续修改时的中间结果
+func (lyt *LayoutManager) BatchUpdate(fn func()) {
+	if lyt.batching {
+		fn()
+		return
+	}
+	lyt.batching = true
+	func() {
+		// fn 中 panic 时也要恢复, 否则之后的 RelayoutGroup 都不会执行
+		defer func() { lyt.batching = false }()
+		fn()
+	}()
+
+	pending := lyt.pending
+	lyt.pending = nil
+	for _, p := range pending {
+		lyt.RelayoutGroup(p.id, p.fn)
+	}
+}
+
+// fn 为 nil 表示只标记, 不替换已经记录的内容函数
+func (lyt *LayoutManager) markRelayout(id ID, fn func()) {
+	for i := range lyt.pending {
+		if lyt.pending[i].id == id {
+			if fn != nil {
+				lyt.pending[i].fn = fn
+			}
+			return
+		}
+	}
+	lyt.pending = append(lyt.pending, pendingRelayout{id, fn})
+}
+
+// child 的大小从 old 变成了现在的大小, 修正所有上层 Group
+func (lyt *LayoutManager) propagate(child *Element, old Bound) {
+	for depth := 0; depth < len(lyt.uiElements); depth++ {
+		p, ok := lyt.Element(child.parent)
+		if !ok || p == child {
+			return
+		}
+		dw, dh := child.W-old.W, child.H-old.H
+		if dw == 0 && dh == 0 {
+			return
+		}
+		r0, b0 := lyt.farEdge(p.id, child.id, old)
+
+		// 后面的兄弟元素让出位置
+		var dx, dy float32
+		switch p.layout {
+		case LinearHorizontal:
+			dx = dw
+		case LinearVertical:
+			dy = dh
+		}
+		if dx != 0 || dy != 0 {
+			for i := range lyt.uiElements {
+				sib := &lyt.uiElements[i]
+				if sib.parent != p.id || sib == p || sib == child {
+					continue
+				}
+				b := lyt.absBound(sib)
+				if (dx != 0 && b.X >= old.X+old.W) || (dy != 0 && b.Y >= old.Y+old.H) {
+					sib.X, sib.Y = sib.X+dx, sib.Y+dy
+					if sib.group {
+						lyt.shiftGroup(sib.id, dx, dy)
+					}
+				}
+			}
+		}
+
+		// SetSize 固定的方向大小不变, 不再向上修正
+		r1, b1 := lyt.farEdge(p.id, -1, Bound{})
+		old = p.Bound
+		if !p.fixedW {
+			p.W += r1-r0
+		}
+		if !p.fixedH {
+			p.H += b1-b0
+		}
+		child = p
+	}
+}
+
+// Group 的子元素最右边和最下边的绝对坐标(包括 margin), id 为 replace 的元素使用 b 代替
+func (lyt *LayoutManager) farEdge(group, replace ID, b Bound) (right, bottom float32) {
+	for i := range lyt.uiElements {
+		e := &lyt.uiElements[i]
+		if e.parent != group || e.id == group {
+			continue
+		}
+		eb := lyt.absBound(e)
+		if e.id == replace {
+			eb = b
+		}
+		if r := eb.X + eb.W + e.Right; r > right {
+			right = r
+		}
+		if bt := eb.Y + eb.H + e.Bottom; bt > bottom {
+			bottom = bt
+		}
+	}
+	return
+}
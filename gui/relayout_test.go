@@ -0,0 +1,191 @@
+package gui
+
+import (
+	"testing"
+)
+
+func TestRelayoutGroup(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	var h float32 = 20
+	panel := func(id ID, h float32) func() {
+		return func() {
+			elem, _ := lyt.BeginElement(id + 1)
+			elem.Size(20, h)
+			lyt.EndElement(elem)
+		}
+	}
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		for _, id := range []ID{2, 4} {
+			lyt.PushLayout(LinearVertical, layoutOf(lyt, id))
+			panel(id, h)()
+			lyt.EndLayout()
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	bound := func(id ID) Bound {
+		elem, _ := lyt.Element(id)
+		return lyt.absBound(elem)
+	}
+	first, second := bound(3), bound(5)
+
+	if !lyt.RelayoutGroup(2, panel(2, 50)) {
+		t.Fatal("relayout failed")
+	}
+	if b := bound(2); b != (Bound{0, 0, 20, 50}) {
+		t.Error("relayout panel:", b)
+	}
+	if b := bound(3); b != (Bound{0, 0, 20, 50}) {
+		t.Error("relayout content:", b, first)
+	}
+	// the following panel moves down as a whole
+	if b := bound(5); b != (Bound{second.X, second.Y + 30, second.W, second.H}) {
+		t.Error("following panel:", b, second)
+	}
+	if b := bound(1); b.H != 70 {
+		t.Error("ancestor height:", b)
+	}
+
+	// a change in the last panel leaves the first one alone
+	lyt.RelayoutGroup(4, func() {
+		elem, _ := lyt.BeginElement(5)
+		elem.Size(60, 20)
+		lyt.EndElement(elem)
+	})
+	if b := bound(3); b != (Bound{0, 0, 20, 50}) {
+		t.Error("unrelated panel should keep its bounds:", b)
+	}
+	if b := bound(1); b.W != 60 || b.H != 70 {
+		t.Error("ancestor size:", b)
+	}
+	if lyt.RelayoutGroup(3, func() {}) {
+		t.Error("relayout of a non-group should fail")
+	}
+}
+
+func TestRelayoutGroupFixedParent(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	clip := Bound{0, 0, 200, 100}
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetSize(200, 200)
+		lyt.PushClip(clip)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 10))
+		for _, id := range []ID{11, 12} {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(20, 20)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+		lyt.PopClip()
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	lyt.RelayoutGroup(10, func() {
+		elem, _ := lyt.BeginElement(13)
+		elem.Size(20, 50)
+		lyt.EndElement(elem)
+	})
+	elem, _ := lyt.Element(1)
+	if b := lyt.absBound(elem); b.W != 200 || b.H != 200 {
+		t.Error("fixed parent should keep its size:", b)
+	}
+	for _, id := range []ID{11, 12} {
+		if lyt.Has(id) {
+			t.Error("element not declared again should be dropped:", id)
+		}
+	}
+	if elem, ok := lyt.Element(13); !ok || !elem.clipped || elem.clip != clip {
+		t.Error("new content should keep the group's clip")
+	}
+}
+
+func TestBatchUpdate(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	var passes int
+	var w, h float32 = 20, 20
+	lyt.SetMeasure(3, func(maxW, maxH float32) (float32, float32) {
+		passes++
+		return w, h
+	})
+	panel := func() {
+		elem, _ := lyt.BeginElement(3)
+		lyt.EndElement(elem)
+	}
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 2))
+		panel()
+		lyt.EndLayout()
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	// 一次重新布局的测量次数(包括交叉轴的第二遍测量)
+	passes = 0
+	lyt.RelayoutGroup(2, panel)
+	single := passes
+
+	passes = 0
+	lyt.BatchUpdate(func() {
+		w = 40
+		lyt.RelayoutGroup(2, panel)
+		h = 30
+		lyt.RelayoutGroup(2, panel)
+		if passes != 0 {
+			t.Error("no measure pass should run inside the batch")
+		}
+	})
+	if passes != single {
+		t.Error("expected a single relayout, measured", passes, "times instead of", single)
+	}
+	if b, _ := lyt.BoundsOf(1); b.W != 40 || b.H != 30 {
+		t.Error("ancestor should reflect the batched changes:", b)
+	}
+
+	// Set* 只标记 Group, 用上一次的内容函数重新布局一次
+	passes = 0
+	lyt.BatchUpdate(func() {
+		lyt.SetMeasure(3, func(maxW, maxH float32) (float32, float32) {
+			passes++
+			return 50, 30
+		})
+		lyt.SetMeasure(3, func(maxW, maxH float32) (float32, float32) {
+			passes++
+			return 60, 30
+		})
+		if passes != 0 {
+			t.Error("setters inside the batch should not relayout")
+		}
+	})
+	if passes != single {
+		t.Error("expected a single relayout after Set*, measured", passes, "times instead of", single)
+	}
+	if b, _ := lyt.BoundsOf(1); b.W != 60 {
+		t.Error("ancestor should reflect the batched setter:", b)
+	}
+
+	// fn 中 panic 之后不能一直处于 batching 状态
+	func() {
+		defer func() { recover() }()
+		lyt.BatchUpdate(func() { panic("boom") })
+	}()
+	if lyt.batching {
+		t.Error("batching should be reset after a panic")
+	}
+}
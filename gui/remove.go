@@ -0,0 +1,136 @@
+package gui
+
+import (
+	"korok.io/korok/engi/math"
+)
+
+// 从当前 Group 中移除一个已经声明的子元素(EndElement 之后), 比如列表项在本帧被删除了
+// Group 的大小按剩下的子元素重新计算, 线性布局中后面的元素补上空位(其它空白不变), 光标也跟着回退
+// 只能移除普通元素, 元素不在当前 Group 中时返回 false
+func (lyt *LayoutManager) Remove(id ID) bool {
+	var (
+		g = lyt.hGroup
+		pos = -1
+	)
+	for i, ii := range g.children {
+		if e := &lyt.uiElements[ii]; e.id == id && !e.group {
+			pos = i
+		}
+	}
+	if pos < 0 {
+		return false
+	}
+	ii := g.children[pos]
+	g.children = append(g.children[:pos], g.children[pos+1:]...)
+	for i := range g.spacers {
+		if g.spacers[i].index > pos {
+			g.spacers[i].index--
+		}
+	}
+	if g.LayoutType == LinearHorizontal || g.LayoutType == LinearVertical {
+		lyt.unstack(g, pos, &lyt.uiElements[ii])
+	} else {
+		lyt.remeasure(g)
+	}
+	lyt.dropElement(ii)
+	return true
+}
+
+// 线性布局中第 pos 个及以后的子元素向前移动被删除的元素占据的大小(包括 spacing),
+// ReserveSize, Offset 等留下的空白保持不变
+func (lyt *LayoutManager) unstack(g *Group, pos int, removed *Element) {
+	var (
+		horizontal = g.LayoutType == LinearHorizontal
+		dx, dy = g.Extent(removed)
+		main, cross = dy, dx
+	)
+	if horizontal {
+		main, cross = dx, dy
+	}
+	d := main + g.Spacing
+	lyt.arrange(g, func(g *Group, children []*Element) {
+		for _, c := range children[pos:] {
+			if horizontal {
+				c.X -= d
+			} else {
+				c.Y -= d
+			}
+		}
+	})
+	// 被删除的元素之前的空白留给后面的元素
+	if pos < len(g.children) {
+		lyt.uiElements[g.children[pos]].lead += removed.lead
+	}
+	if g.count--; g.count == 0 {
+		d = main
+	}
+	// 交叉轴的大小只在被删除的元素最大时重新计算
+	size, crossSize := &g.Size.H, &g.Size.W
+	if horizontal {
+		size, crossSize = &g.Size.W, &g.Size.H
+		lyt.Cursor.X -= main + g.Spacing
+	} else {
+		lyt.Cursor.Y -= main + g.Spacing
+	}
+	*size = math.Max(*size-d, 0)
+	g.next -= main + g.Spacing
+	if cross >= *crossSize {
+		*crossSize = 0
+		for _, ii := range g.children {
+			cx, cy := g.Extent(&lyt.uiElements[ii])
+			if horizontal {
+				cx = cy
+			}
+			*crossSize = math.Max(*crossSize, cx)
+		}
+	}
+}
+
+// 按现有的子元素重新计算 Group 的大小
+func (lyt *LayoutManager) remeasure(g *Group) {
+	l, ok := layouters[g.LayoutType]
+	if !ok {
+		return
+	}
+	var c Bound
+	g.Size.W, g.Size.H, g.count = 0, 0, 0
+	for _, ii := range g.children {
+		elem := &lyt.uiElements[ii]
+		l.Measure(g, elem)
+		l.Place(g, elem, &c)
+		g.count++
+	}
+}
+
+// 从 uiElements 中删除第 ii 个元素, 修正所有指向后面元素的索引和指针
+func (lyt *LayoutManager) dropElement(ii int) {
+	// 打开的 Group 指向 uiElements, 删除之后要指向前一个位置
+	moved := make([]bool, len(lyt.groupStack))
+	for i := range lyt.groupStack {
+		moved[i] = lyt.index(lyt.groupStack[i].id) > ii
+	}
+	delete(lyt.ids, lyt.uiElements[ii].id)
+	copy(lyt.uiElements[ii:], lyt.uiElements[ii+1:])
+	lyt.uiElements = lyt.uiElements[:len(lyt.uiElements)-1]
+	for k := ii; k < len(lyt.uiElements); k++ {
+		e := &lyt.uiElements[k]
+		lyt.ids[e.id] = k
+		// 已经结束的子 Group
+		for j, c := range e.kids {
+			if c > ii {
+				e.kids[j] = c - 1
+			}
+		}
+	}
+	for i := range lyt.groupStack {
+		g := &lyt.groupStack[i]
+		if moved[i] {
+			g.Element = &lyt.uiElements[lyt.index(g.id)]
+		}
+		for j, c := range g.children {
+			if c > ii {
+				g.children[j] = c - 1
+			}
+		}
+	}
+}
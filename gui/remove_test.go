@@ -0,0 +1,80 @@
+package gui
+
+import "testing"
+
+func TestRemove(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func(remove bool) {
+		lyt.BeginFrame()
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		for id := ID(2); id <= 4; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(50, 20)
+			lyt.EndElement(elem)
+		}
+		if remove && !lyt.Remove(3) {
+			t.Error("remove failed")
+		}
+		// 光标跟着回退
+		elem, _ := lyt.BeginElement(5)
+		elem.Size(50, 10)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+		lyt.EndFrame()
+	}
+	frame(false)
+	frame(false)
+	frame(true)
+
+	if g, _ := lyt.BoundsOf(1); g.H != 50 || g.W != 50 {
+		t.Error("group size should only count the remaining children:", g)
+	}
+	if lyt.Has(3) {
+		t.Error("removed element should be gone")
+	}
+	for id, y := range map[ID]float32{2: 0, 4: 20, 5: 40} {
+		if b, _ := lyt.BoundsOf(id); b.Y != y {
+			t.Error("element", id, "placed at:", b.Y, "expected:", y)
+		}
+	}
+	if lyt.Remove(42) {
+		t.Error("removing an unknown element should fail")
+	}
+}
+
+func TestRemoveKeepsGaps(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func(remove ID) {
+		lyt.BeginFrame()
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.ReserveSize(10, 500)
+		for id := ID(2); id <= 4; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(50, 20)
+			lyt.EndElement(elem)
+		}
+		if remove != 0 {
+			lyt.Remove(remove)
+		}
+		lyt.EndLayout()
+		lyt.EndFrame()
+	}
+	frame(0)
+	frame(0)
+	frame(3)
+
+	if g, _ := lyt.BoundsOf(1); g.H != 540 || g.W != 50 {
+		t.Error("group should keep the reserved space:", g)
+	}
+	for id, y := range map[ID]float32{2: 500, 4: 520} {
+		if b, _ := lyt.BoundsOf(id); b.Y != y {
+			t.Error("element", id, "placed at:", b.Y, "expected:", y)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package gui
+
+// 根布局大小变化后, 元素的绝对坐标从 old 变成了 b
+type ResizeFunc func(id ID, old, b Bound)
+
+// 等待下一帧处理的窗口大小变化, 见 OnResize
+type resizeState struct {
+	pending bool
+	// 大小变化之前每个根布局中元素的绝对坐标
+	from map[resizeKey]Bound
+	handlers map[ID]ResizeFunc
+}
+
+// 不同的根布局中可以有相同的 ID
+type resizeKey struct {
+	layer int
+	id ID
+}
+
+// 窗口大小变化时调用, 更新根布局的大小(见 SetRootSize) 并清除 BeginCached 的缓存,
+// 下一次 BeginFrame 会重新布局所有的根布局, 即使布局被冻结了(SetFrozen).
+// 这一帧的 EndFrame 对坐标变化了的元素调用 SetResizeHandler 设置的回调.
+// 下一帧之前多次调用时, 以第一次调用之前的坐标为准
+func (lyt *LayoutManager) OnResize(w, h float32) {
+	r := &lyt.resize
+	if !r.pending {
+		if r.from == nil {
+			r.from = make(map[resizeKey]Bound)
+		}
+		for k := range r.from {
+			delete(r.from, k)
+		}
+		lyt.eachLayer(func() {
+			for i := 1; i < len(lyt.uiElements); i++ {
+				elem := &lyt.uiElements[i]
+				r.from[resizeKey{lyt.active, elem.id}] = lyt.absBound(elem)
+			}
+		})
+	}
+	r.pending = true
+	lyt.cache.valid = false
+	lyt.SetRootSize(finite(w), finite(h))
+}
+
+// 设置元素在窗口大小变化(OnResize)后坐标变化时的回调, fn 为 nil 时删除
+func (lyt *LayoutManager) SetResizeHandler(id ID, fn ResizeFunc) {
+	r := &lyt.resize
+	if fn == nil {
+		delete(r.handlers, id)
+		return
+	}
+	if r.handlers == nil {
+		r.handlers = make(map[ID]ResizeFunc)
+	}
+	r.handlers[id] = fn
+}
+
+// 在 EndFrame 中调用, 通知坐标变化了的元素
+func (lyt *LayoutManager) emitResize() {
+	r := &lyt.resize
+	if !r.pending {
+		return
+	}
+	r.pending = false
+	lyt.eachLayer(func() {
+		for id, fn := range r.handlers {
+			old, ok := r.from[resizeKey{lyt.active, id}]
+			if !ok {
+				continue
+			}
+			if elem, ok := lyt.Element(id); ok {
+				if b := lyt.absBound(elem); b != old {
+					fn(id, old, b)
+				}
+			}
+		}
+	})
+}
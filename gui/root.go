@@ -0,0 +1,66 @@
+package gui
+
+// 设置元素的基线(到元素顶部的距离), 一般是文字的 ascent
+func (lyt *LayoutManager) SetBaseline(id ID, offset float32) {
+	elem := lyt.obtain(id)
+	elem.baseline, elem.hasBaseline = offset, true
+}
+
+// 根布局测量出的大小, 用来把 GUI 嵌入到别的布局系统中
+func (lyt *LayoutManager) RootSize() Bound {
+	if len(lyt.groupStack) == 0 {
+		return Bound{}
+	}
+	root := &lyt.groupStack[0]
+	pad := root.Padding
+	return Bound{root.X, root.Y, root.Size.W + pad.Left + pad.Right, root.Size.H + pad.Top + pad.Bottom}
+}
+
+// 根布局的第一条基线(相对于根布局的顶部), 按声明的顺序找第一个设置了基线的元素,
+// 如果没有, 返回根布局的底部
+func (lyt *LayoutManager) RootBaseline() float32 {
+	root := lyt.RootSize()
+	for i := 1; i < len(lyt.uiElements); i++ {
+		if elem := &lyt.uiElements[i]; elem.hasBaseline {
+			return lyt.absBound(elem).Y + elem.baseline - root.Y
+		}
+	}
+	return root.H
+}
+
+// 按根布局的大小计算 WidthVW/HeightVH
+func (lyt *LayoutManager) applyViewport(elem *Element) {
+	if len(lyt.groupStack) == 0 {
+		return
+	}
+	root := &lyt.groupStack[0]
+	if elem.vw > 0 {
+		elem.W = root.W * elem.vw / 100
+	}
+	if elem.vh > 0 {
+		elem.H = root.H * elem.vh / 100
+	}
+}
+
+// 设置根布局(视口)的大小, 比如窗口大小或者屏幕旋转之后
+// 大小变化时, 依赖旧大小的缓存(BeginCached)和上一帧的坐标(PrevBound)都会失效,
+// 避免旧的结果被当成元素的移动
+func (lyt *LayoutManager) SetRootSize(w, h float32) {
+	changed := !lyt.hasRootSize || lyt.rootSize.W != w || lyt.rootSize.H != h
+	lyt.rootSize.W, lyt.rootSize.H = w, h
+	lyt.hasRootSize = true
+	if len(lyt.groupStack) > 0 {
+		root := &lyt.groupStack[0]
+		root.W, root.H = w, h
+		root.hasSize = true
+	}
+	if changed {
+		lyt.rootChanged = true
+		lyt.cache.valid = false
+		lyt.eachLayer(func() {
+			for k := range lyt.prevBounds {
+				delete(lyt.prevBounds, k)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package gui
+
+import (
+	"testing"
+)
+
+func TestRootSize(t *testing.T) {
+	lyt := New()
+	lyt.SetBaseline(3, 10)
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetPadding(2, 2, 2, 2)
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(40, 12)
+		lyt.EndElement(elem)
+		elem, _ = lyt.BeginElement(3)
+		elem.Size(30, 12)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	// padding + 2 rows + gap
+	if size := lyt.RootSize(); size.W != 44 || size.H != 2+12+lyt.spacing+12+2 {
+		t.Error("root size:", size)
+	}
+	if b := lyt.RootBaseline(); b != 2+12+lyt.spacing+10 {
+		t.Error("root baseline:", b)
+	}
+}
+
+func TestViewportUnits(t *testing.T) {
+	lyt := New()
+	lyt.SetSize(400, 300)
+
+	p := NewProperty()
+	p.WidthVW, p.HeightVH = 50, 10
+
+	frame := func() {
+		lyt.Move(0, 0)
+		for id := ID(1); id <= 3; id++ {
+			lyt.PushLayout(LinearVertical, layoutOf(lyt, id))
+			lyt.SetPadding(5, 5, 5, 5)
+		}
+		elem, _ := lyt.BeginElement(4)
+		lyt.EndElement(elem)
+		for id := ID(1); id <= 3; id++ {
+			lyt.EndLayout()
+		}
+	}
+	frame()
+	lyt.SetProperty(4, p)
+	frame()
+
+	if elem, _ := lyt.Element(4); elem.W != 200 || elem.H != 30 {
+		t.Error("nested element should be sized by the root:", elem.Bound)
+	}
+}
+
+func TestSetRootSize(t *testing.T) {
+	lyt := New()
+	lyt.SetRootSize(400, 300)
+
+	p := NewProperty()
+	p.WidthVW, p.HeightVH = 50, 50
+
+	frame := func() {
+		lyt.BeginFrame()
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		elem, _ := lyt.BeginElement(2)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+		lyt.EndFrame()
+	}
+	frame()
+	lyt.SetProperty(2, p)
+	frame()
+	lyt.BeginCached(1)
+	lyt.EndCached()
+
+	if elem, _ := lyt.Element(2); elem.W != 200 || elem.H != 150 {
+		t.Error("element before rotation:", elem.Bound)
+	}
+
+	// rotate
+	lyt.SetRootSize(300, 400)
+	if _, ok := lyt.PrevBound(2); ok {
+		t.Error("prev bounds should be invalidated")
+	}
+	if lyt.BeginCached(1) {
+		t.Error("layout cache should be invalidated")
+	}
+	frame()
+	if elem, _ := lyt.Element(2); elem.W != 150 || elem.H != 200 {
+		t.Error("element after rotation:", elem.Bound)
+	}
+	if _, ok := lyt.PrevBound(2); ok {
+		t.Error("bounds from before the rotation should not be reported")
+	}
+}
+
+func TestOnResize(t *testing.T) {
+	lyt := New()
+	lyt.SetRootSize(400, 300)
+
+	p := NewProperty()
+	p.WidthVW, p.HeightVH = 50, 10
+
+	frame := func() {
+		if lyt.BeginFrame() {
+			return
+		}
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		for id := ID(2); id <= 3; id++ {
+			elem, _ := lyt.BeginElement(id)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+		lyt.EndFrame()
+	}
+	frame()
+	lyt.SetProperty(2, p)
+	lyt.SetProperty(3, p)
+	frame()
+
+	resized := map[ID]Bound{}
+	for id := ID(2); id <= 3; id++ {
+		lyt.SetResizeHandler(id, func(id ID, old, b Bound) {
+			resized[id] = b
+		})
+	}
+	lyt.SetFrozen(true)
+	lyt.OnResize(800, 200)
+	frame()
+
+	if elem, _ := lyt.Element(2); elem.W != 400 || elem.H != 20 {
+		t.Error("percentage sized child should reflow:", elem.Bound)
+	}
+	if b := resized[3]; b.W != 400 || b.H != 20 || len(resized) != 2 {
+		t.Error("resize handlers should see the new bounds:", resized)
+	}
+
+	// 大小没有变化的元素不会收到回调
+	resized = map[ID]Bound{}
+	lyt.OnResize(800, 200)
+	frame()
+	if len(resized) != 0 {
+		t.Error("unchanged elements should not be notified:", resized)
+	}
+	if !lyt.BeginFrame() {
+		t.Error("layout should stay frozen after the resize")
+	}
+}
+
+func TestOnResizeLayers(t *testing.T) {
+	lyt := New()
+	lyt.SetRootSize(400, 300)
+
+	p := NewProperty()
+	p.WidthVW, p.HeightVH = 50, 10
+	lyt.SetProperty(2, p)
+	lyt.Root("hud")
+	lyt.SetProperty(2, p)
+
+	frame := func() {
+		lyt.BeginFrame()
+		for _, name := range []string{"", "hud"} {
+			lyt.Root(name)
+			lyt.Move(0, 0)
+			lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+			elem, _ := lyt.BeginElement(2)
+			lyt.EndElement(elem)
+			lyt.EndLayout()
+		}
+		lyt.EndFrame()
+	}
+	frame()
+	frame()
+
+	var calls int
+	lyt.SetResizeHandler(2, func(id ID, old, b Bound) {
+		calls++
+		if b.W != 400 {
+			t.Error("resize handler should see the new bounds:", b)
+		}
+	})
+	// 当前是 hud, 背景的根布局也要收到回调
+	lyt.OnResize(800, 200)
+	frame()
+	if calls != 2 {
+		t.Error("both layers should be notified, calls:", calls)
+	}
+
+	// 大小没有变化时也要清除缓存
+	if !lyt.BeginCached(1) {
+		lyt.EndCached()
+	}
+	lyt.OnResize(800, 200)
+	if lyt.BeginCached(1) {
+		t.Error("OnResize should invalidate the layout cache")
+	}
+}
@@ -0,0 +1,215 @@
+package gui
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+	"korok.io/korok/engi/math"
+)
+
+// 设置 Group 的滚动偏移, 子元素会向相反的方向移动
+func (lyt *LayoutManager) SetScroll(id ID, x, y float32) {
+	if elem, ok := lyt.Element(id); ok {
+		lyt.applyScroll(elem, mgl32.Vec2{x, y})
+	}
+}
+
+// 修改滚动偏移. 子 Group 的坐标是绝对坐标(包含了上层所有的滚动偏移),
+// 所以要一起移动, 子 Group 中设置的裁剪区域也一样,
+// 这样嵌套的滚动容器不需要重新布局就能得到正确的位置
+func (lyt *LayoutManager) applyScroll(elem *Element, offset mgl32.Vec2) {
+	offset = elem.lockScroll(offset)
+	d := elem.scroll.Sub(offset)
+	elem.scroll = offset
+	lyt.shiftGroup(elem.id, d[0], d[1])
+	lyt.shiftClips(elem.id, d)
+}
+
+// 在当前的滚动偏移上增加 (dx, dy), 限制在滚动范围之内
+func (lyt *LayoutManager) ScrollBy(id ID, dx, dy float32) {
+	if elem, ok := lyt.Element(id); ok {
+		delete(lyt.scrolling, id)
+		var (
+			offset = elem.scroll.Add(mgl32.Vec2{dx, dy})
+			limit = elem.maxScroll()
+		)
+		offset[0] = math.F32Clamp(offset[0], 0, limit[0])
+		offset[1] = math.F32Clamp(offset[1], 0, limit[1])
+		lyt.applyScroll(elem, offset)
+	}
+}
+
+// 当前 Group 只能沿 axis 方向滚动, 另一个方向的偏移始终为 0, 比如水平的轮播
+func (lyt *LayoutManager) SetScrollAxis(axis Axis) *LayoutManager {
+	g := lyt.hGroup
+	g.scrollAxis, g.scrollLocked = axis, true
+	g.scroll = g.lockScroll(g.scroll)
+	return lyt
+}
+
+// 去掉被锁定方向的偏移
+func (elem *Element) lockScroll(offset mgl32.Vec2) mgl32.Vec2 {
+	if elem.scrollLocked {
+		if elem.scrollAxis == AxisX {
+			offset[1] = 0
+		} else {
+			offset[0] = 0
+		}
+	}
+	return offset
+}
+
+// 返回 Group 的滚动偏移
+func (lyt *LayoutManager) Scroll(id ID) (offset mgl32.Vec2, ok bool) {
+	var elem *Element
+	if elem, ok = lyt.Element(id); ok {
+		offset = elem.scroll
+	}
+	return
+}
+
+// 绘制滚动条需要的信息, 都是绝对坐标
+// content 是滚动后内容的区域, viewport 是 Group 的区域, offset 同 Scroll
+func (lyt *LayoutManager) ScrollInfo(id ID) (content, viewport Bound, offset mgl32.Vec2, ok bool) {
+	var elem *Element
+	if elem, ok = lyt.Element(id); !ok {
+		return
+	}
+	viewport, offset = lyt.absBound(elem), elem.scroll
+	content = Bound{viewport.X - offset[0], viewport.Y - offset[1], elem.content.W, elem.content.H}
+	return
+}
+
+// 最大的滚动偏移, 即内容超出 Group 的部分
+func (elem *Element) maxScroll() mgl32.Vec2 {
+	return mgl32.Vec2{
+		math.Max(elem.content.W-elem.W, 0),
+		math.Max(elem.content.H-elem.H, 0),
+	}
+}
+
+// 滚动到指定的位置, align = 0 为起点, 1 为终点
+func (lyt *LayoutManager) ScrollTo(id ID, align float32) {
+	if elem, ok := lyt.Element(id); ok {
+		delete(lyt.scrolling, id)
+		lyt.applyScroll(elem, elem.maxScroll().Mul(math.F32Clamp(align, 0, 1)))
+	}
+}
+
+type scrollAnim struct {
+	start, target mgl32.Vec2
+	elapsed, duration float32
+}
+
+// 和 ScrollTo 一样, 但是在 duration 秒内逐渐滚动过去, 需要每帧调用 TickScroll
+func (lyt *LayoutManager) AnimateScrollTo(id ID, align, duration float32) {
+	elem, ok := lyt.Element(id)
+	if !ok {
+		return
+	}
+	if duration <= 0 {
+		lyt.ScrollTo(id, align)
+		return
+	}
+	if lyt.scrolling == nil {
+		lyt.scrolling = make(map[ID]*scrollAnim)
+	}
+	lyt.scrolling[id] = &scrollAnim{
+		start: elem.scroll,
+		target: elem.lockScroll(elem.maxScroll().Mul(math.F32Clamp(align, 0, 1))),
+		duration: duration,
+	}
+}
+
+// 更新滚动动画, 使用 smoothstep 插值, 并限制在滚动范围之内
+func (lyt *LayoutManager) TickScroll(dt float32) {
+	for id, anim := range lyt.scrolling {
+		elem, ok := lyt.Element(id)
+		if !ok {
+			delete(lyt.scrolling, id)
+			continue
+		}
+		anim.elapsed += dt
+		t := math.F32Clamp(anim.elapsed/anim.duration, 0, 1)
+		t = t * t * (3 - 2*t)
+
+		var (
+			offset = anim.start.Add(anim.target.Sub(anim.start).Mul(t))
+			limit = elem.maxScroll()
+		)
+		offset[0] = math.F32Clamp(offset[0], 0, limit[0])
+		offset[1] = math.F32Clamp(offset[1], 0, limit[1])
+		lyt.applyScroll(elem, offset)
+
+		if anim.elapsed >= anim.duration {
+			delete(lyt.scrolling, id)
+		}
+	}
+}
+
+// 滚动条滑块的位置和大小(绝对坐标), 滑动轨道是 Group 本身的范围,
+// 交叉方向和 Group 一样大, 绘制时可以自己决定滑块的粗细.
+// 内容没有超出 Group 时不需要滚动条, 返回 false
+func (lyt *LayoutManager) ScrollThumb(id ID, axis Axis) (thumb Bound, ok bool) {
+	elem, ok := lyt.Element(id)
+	if !ok {
+		return
+	}
+	track := lyt.absBound(elem)
+	i, view, content := thumbAxis(elem, axis)
+	if content <= view || view <= 0 {
+		return Bound{}, false
+	}
+	var (
+		length = math.Min(math.Max(view*view/content, lyt.minThumb), view)
+		offset = (view - length) * math.F32Clamp(elem.scroll[i]/(content-view), 0, 1)
+	)
+	thumb = track
+	if axis == AxisX {
+		thumb.X, thumb.W = track.X+offset, length
+	} else {
+		thumb.Y, thumb.H = track.Y+offset, length
+	}
+	return thumb, true
+}
+
+// 滑块的最小长度, 内容很长时滑块不会缩成一条细线.
+// 滑块变长后可以滑动的距离变短, DragThumb 会按新的距离换算, 仍然可以滚动到两端
+func (lyt *LayoutManager) SetMinThumbSize(px float32) *LayoutManager {
+	lyt.minThumb = math.Max(finite(px), 0)
+	return lyt
+}
+
+// 拖动滑块 delta(布局单位), 换算成内容的滚动偏移
+func (lyt *LayoutManager) DragThumb(id ID, axis Axis, delta float32) {
+	thumb, ok := lyt.ScrollThumb(id, axis)
+	if !ok {
+		return
+	}
+	elem, _ := lyt.Element(id)
+	i, view, content := thumbAxis(elem, axis)
+	length := thumb.H
+	if axis == AxisX {
+		length = thumb.W
+	}
+	if free := view - length; free > 0 {
+		delete(lyt.scrolling, id)
+		offset := elem.scroll
+		offset[i] = math.F32Clamp(offset[i]+delta*(content-view)/free, 0, content-view)
+		lyt.applyScroll(elem, offset)
+	}
+}
+
+func thumbAxis(elem *Element, axis Axis) (i int, view, content float32) {
+	if axis == AxisX {
+		return 0, elem.W, elem.content.W
+	}
+	return 1, elem.H, elem.content.H
+}
+
+// Group 的内容是否超出了 Group 的大小(比如被 SetMaxSize 限制了)
+func (lyt *LayoutManager) Overflowed(id ID) bool {
+	if elem, ok := lyt.Element(id); ok {
+		m := elem.maxScroll()
+		return m[0] > 0 || m[1] > 0
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// a 100x100 vertical group with 300px content
+func scrollFrame(lyt *LayoutManager) {
+	lyt.Move(0, 0)
+	lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+	lyt.SetSize(100, 100)
+	for id := ID(2); id <= 4; id++ {
+		elem, _ := lyt.BeginElement(id)
+		elem.Size(100, 100)
+		lyt.EndElement(elem)
+	}
+	lyt.EndLayout()
+}
+
+func TestAnimateScrollTo(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+	scrollFrame(lyt)
+	scrollFrame(lyt)
+
+	lyt.AnimateScrollTo(1, 1, 1)
+	lyt.TickScroll(.5)
+	if offset, _ := lyt.Scroll(1); offset != (mgl32.Vec2{0, 100}) {
+		t.Error("halfway scroll offset:", offset)
+	}
+
+	lyt.TickScroll(.75)
+	if offset, _ := lyt.Scroll(1); offset != (mgl32.Vec2{0, 200}) {
+		t.Error("scroll offset should stop at target:", offset)
+	}
+	if len(lyt.scrolling) != 0 {
+		t.Error("scroll animation should be completed")
+	}
+}
+
+func TestScrollThumb(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+	scrollFrame(lyt)
+	scrollFrame(lyt)
+
+	near := func(a, b float32) bool {
+		return a-b < .01 && b-a < .01
+	}
+	// viewport 100, content 300: thumb is a third of the track
+	for _, c := range []struct{ scroll, y float32 }{{0, 0}, {100, 100.0 / 3}, {200, 200.0 / 3}} {
+		lyt.SetScroll(1, 0, c.scroll)
+		thumb, ok := lyt.ScrollThumb(1, AxisY)
+		if !ok || !near(thumb.Y, c.y) || !near(thumb.H, 100.0/3) || thumb.X != 0 || thumb.W != 100 {
+			t.Error("thumb at scroll", c.scroll, ":", thumb, ok)
+		}
+	}
+	if _, ok := lyt.ScrollThumb(1, AxisX); ok {
+		t.Error("no horizontal overflow, no thumb")
+	}
+
+	// dragging the thumb by a third of the track scrolls by one viewport
+	lyt.SetScroll(1, 0, 0)
+	lyt.DragThumb(1, AxisY, 100.0/3)
+	if offset, _ := lyt.Scroll(1); !near(offset[1], 100) {
+		t.Error("drag thumb:", offset)
+	}
+}
+
+func TestScrollAxis(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	// a 100x100 carousel with 300x300 content
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		lyt.SetSize(100, 100).SetScrollAxis(AxisX)
+		for id := ID(2); id <= 4; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(100, 300)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	lyt.ScrollBy(1, 30, 50)
+	if offset, _ := lyt.Scroll(1); offset != (mgl32.Vec2{30, 0}) {
+		t.Error("vertical delta should be ignored:", offset)
+	}
+	lyt.SetScroll(1, 10, 20)
+	if offset, _ := lyt.Scroll(1); offset != (mgl32.Vec2{10, 0}) {
+		t.Error("vertical offset should be ignored:", offset)
+	}
+	lyt.ScrollBy(1, 1000, 0)
+	if offset, _ := lyt.Scroll(1); offset != (mgl32.Vec2{200, 0}) {
+		t.Error("scroll should be clamped:", offset)
+	}
+}
+
+func TestScrollInfo(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+	scrollFrame(lyt)
+	scrollFrame(lyt)
+	lyt.SetScroll(1, 0, 50)
+
+	content, viewport, offset, ok := lyt.ScrollInfo(1)
+	if !ok {
+		t.Fatal("scroll info not found")
+	}
+	if content != (Bound{0, -50, 100, 300}) {
+		t.Error("content extent:", content)
+	}
+	if viewport != (Bound{0, 0, 100, 100}) {
+		t.Error("viewport extent:", viewport)
+	}
+	if offset != (mgl32.Vec2{0, 50}) {
+		t.Error("scroll offset:", offset)
+	}
+	if _, _, _, ok := lyt.ScrollInfo(99); ok {
+		t.Error("unknown id should not resolve")
+	}
+}
+
+func TestNestedScroll(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	// outer(1) 100x100 > inner(2) 100x100 at y=50 > leaf(4) at y=50
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetSize(100, 100)
+		elem, _ := lyt.BeginElement(3)
+		elem.Size(100, 50)
+		lyt.EndElement(elem)
+
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 2))
+		lyt.SetSize(100, 100)
+		elem, _ = lyt.BeginElement(5)
+		elem.Size(100, 50)
+		lyt.EndElement(elem)
+		elem, _ = lyt.BeginElement(4)
+		elem.Size(100, 200)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	lyt.SetScroll(1, 0, 30)
+	lyt.SetScroll(2, 0, 20)
+	if b, _ := lyt.BoundsOf(4); b.Y != 50+50-30-20 {
+		t.Error("leaf should fold in both scroll offsets:", b)
+	}
+	if id, ok := lyt.HitTest(mgl32.Vec2{10, 55}); !ok || id != 4 {
+		t.Error("hit test should use the scrolled position:", id, ok)
+	}
+
+	// 重新布局得到同样的结果
+	frame()
+	if b, _ := lyt.BoundsOf(4); b.Y != 50 {
+		t.Error("relayout should keep the scrolled position:", b)
+	}
+}
+
+func TestNestedScrollClip(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	// outer(1) 裁剪到 100x100, inner(2) 在 y=50, 裁剪到自己的范围
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetSize(100, 100).SetShrinkToFit(false)
+		lyt.PushClip(Bound{0, 0, 100, 100})
+		elem, _ := lyt.BeginElement(3)
+		elem.Size(100, 50)
+		lyt.EndElement(elem)
+
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 2))
+		lyt.SetSize(100, 100).SetShrinkToFit(false)
+		lyt.PushClip(Bound{0, 50, 100, 100})
+		elem, _ = lyt.BeginElement(4)
+		elem.Size(100, 200)
+		lyt.EndElement(elem)
+		lyt.PopClip()
+		lyt.EndLayout()
+
+		lyt.PopClip()
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	lyt.SetScroll(1, 0, 30)
+	clips := map[ID]Bound{}
+	lyt.Visit(func(info ElementInfo) {
+		clips[info.ID] = info.Clip
+	})
+	if c := clips[3]; c != (Bound{0, 0, 100, 100}) {
+		t.Error("the scrolled group's own clip should not move:", c)
+	}
+	if c := clips[4]; c != (Bound{0, 20, 100, 80}) {
+		t.Error("nested clip should move with the nested group:", c)
+	}
+	if id, ok := lyt.HitTest(mgl32.Vec2{10, 30}); !ok || id != 4 {
+		t.Error("hit test should use the scrolled clip:", id, ok)
+	}
+}
+
+func TestMinThumbSize(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+	lyt.SetMinThumbSize(20)
+
+	// a 100x100 group with 100000px content
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetSize(100, 100)
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(100, 100000)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	thumb, ok := lyt.ScrollThumb(1, AxisY)
+	if !ok || thumb.H != 20 || thumb.Y != 0 {
+		t.Fatal("thumb should be clamped to the minimum:", thumb, ok)
+	}
+
+	// 拖动整个空闲的轨道(80px)滚动到底
+	lyt.DragThumb(1, AxisY, 80)
+	if offset, _ := lyt.Scroll(1); offset[1] != 100000-100 {
+		t.Error("dragging to the end should reach the full range:", offset)
+	}
+	if thumb, _ := lyt.ScrollThumb(1, AxisY); thumb.Y+thumb.H != 100 {
+		t.Error("thumb should sit at the end of the track:", thumb)
+	}
+	lyt.DragThumb(1, AxisY, -80)
+	if offset, _ := lyt.Scroll(1); offset[1] != 0 {
+		t.Error("dragging back should reach the start:", offset)
+	}
+}
@@ -0,0 +1,77 @@
+package gui
+
+import (
+	"sort"
+)
+
+// 设置元素的排序值, 线性布局在 EndLayout 时按排序值(从小到大)重新排列子元素,
+// 排序值相同的保持声明的顺序. 元素之前 ReserveSize/Offset 留下的空白跟着元素一起移动
+func (lyt *LayoutManager) SetSortKey(id ID, key int) {
+	lyt.obtain(id).sortKey = key
+}
+
+func (lyt *LayoutManager) sortChildren(g *Group) {
+	if g.LayoutType != LinearHorizontal && g.LayoutType != LinearVertical {
+		return
+	}
+	keyed := false
+	for _, ii := range g.children {
+		if lyt.uiElements[ii].sortKey != 0 {
+			keyed = true; break
+		}
+	}
+	if !keyed && !g.reverse {
+		return
+	}
+	if keyed {
+		sort.SliceStable(g.children, func(i, j int) bool {
+			return lyt.uiElements[g.children[i]].sortKey < lyt.uiElements[g.children[j]].sortKey
+		})
+	}
+	if g.reverse {
+		for i, j := 0, len(g.children)-1; i < j; i, j = i+1, j-1 {
+			g.children[i], g.children[j] = g.children[j], g.children[i]
+		}
+	}
+	lyt.arrange(g, restack)
+}
+
+// 线性布局在 EndLayout 时按声明的相反顺序排列子元素(在 SetSortKey 之后),
+// 比如最新的消息在最前面. 和 Direction 不同, 坐标的增长方向不变
+func (lyt *LayoutManager) SetReverse(reverse bool) *LayoutManager {
+	lyt.hGroup.reverse = reverse
+	return lyt
+}
+
+// 在当前 Group 中把已经声明的元素 id 移到 beforeID 之前(beforeID 为 -1 时移到最后),
+// 然后重新排列子元素, 不需要重新声明. 配合 PrevBound 可以实现拖动排序的动画
+// 只对线性布局有效, 元素不在当前 Group 中时返回 false
+func (lyt *LayoutManager) MoveElement(id, beforeID ID) bool {
+	g := lyt.hGroup
+	if g.LayoutType != LinearHorizontal && g.LayoutType != LinearVertical {
+		return false
+	}
+	from, to := -1, len(g.children)
+	for i, ii := range g.children {
+		switch lyt.uiElements[ii].id {
+		case id:
+			from = i
+		case beforeID:
+			to = i
+		}
+	}
+	if from < 0 || (beforeID >= 0 && to == len(g.children)) {
+		return false
+	}
+	ii := g.children[from]
+	children := append(g.children[:from:from], g.children[from+1:]...)
+	if to > from {
+		to--
+	}
+	children = append(children, 0)
+	copy(children[to+1:], children[to:])
+	children[to] = ii
+	g.children = children
+	lyt.arrange(g, restack)
+	return true
+}
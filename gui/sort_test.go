@@ -0,0 +1,123 @@
+package gui
+
+import (
+	"testing"
+)
+
+func TestSortKey(t *testing.T) {
+	lyt := New()
+
+	// declared in order 2, 3, 4
+	lyt.SetSortKey(2, 2)
+	lyt.SetSortKey(3, 0)
+	lyt.SetSortKey(4, 1)
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		for id := ID(2); id <= 4; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(50, 20)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	step := 20 + lyt.spacing
+	for i, id := range []ID{3, 4, 2} {
+		if elem, _ := lyt.Element(id); elem.Y != step*float32(i) {
+			t.Error("element", id, "placed at:", elem.Y, "expected:", step*float32(i))
+		}
+	}
+}
+
+func TestMoveElement(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+	for id := ID(2); id <= 4; id++ {
+		elem, _ := lyt.BeginElement(id)
+		elem.Size(20, 10)
+		lyt.EndElement(elem)
+	}
+	if !lyt.MoveElement(4, 2) {
+		t.Fatal("move failed")
+	}
+	expect := map[ID]float32{4: 0, 2: 10, 3: 20}
+	for id, y := range expect {
+		if elem, _ := lyt.Element(id); elem.Y != y {
+			t.Error("element", id, "placed at:", elem.Y, "expected:", y)
+		}
+	}
+
+	// to the end
+	lyt.MoveElement(4, -1)
+	expect = map[ID]float32{2: 0, 3: 10, 4: 20}
+	for id, y := range expect {
+		if elem, _ := lyt.Element(id); elem.Y != y {
+			t.Error("element", id, "placed at:", elem.Y, "expected:", y)
+		}
+	}
+	if lyt.MoveElement(5, 2) {
+		t.Error("element not in the group should not move")
+	}
+	lyt.EndLayout()
+}
+
+func TestReverse(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	// declared in order A(2), B(3), C(4)
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		lyt.SetReverse(true)
+		for id := ID(2); id <= 4; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(10*float32(id), 20)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	for id, x := range map[ID]float32{4: 0, 3: 40, 2: 70} {
+		if elem, _ := lyt.Element(id); elem.X != x {
+			t.Error("element", id, "placed at:", elem.X, "expected:", x)
+		}
+	}
+}
+
+func TestReverseKeepsGaps(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetReverse(true)
+		for id := ID(2); id <= 3; id++ {
+			if id == 3 {
+				lyt.SpacingBefore(30)
+			}
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(20, 10)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	// the extra spacing moves together with element 3
+	for id, y := range map[ID]float32{3: 30, 2: 40} {
+		if elem, _ := lyt.Element(id); elem.Y != y {
+			t.Error("element", id, "placed at:", elem.Y, "expected:", y)
+		}
+	}
+}
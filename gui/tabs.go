@@ -0,0 +1,84 @@
+package gui
+
+import (
+	"korok.io/korok/engi/math"
+)
+
+// 标签栏中被选中的标签, 见 SetSelectedTab
+type tabStrip struct {
+	selected ID
+	share    float32
+	valid    bool
+}
+
+// 把当前线性布局当作标签栏: 子元素的主轴大小被忽略, 按 Group 的大小重新分配,
+// 选中的标签 id 占 share 份, 其它标签各占 1 份, 所有标签(包括 spacing 和 SpacingBefore 等留下的空白)正好填满 Group.
+// spacing 为负数时相邻的标签互相重叠. 子 Group 保持自己的大小, 不参与分配.
+// Group 需要设置主轴方向的大小, 选中的 id 不在 Group 中时平均分配
+func (lyt *LayoutManager) SetSelectedTab(id ID, share float32) *LayoutManager {
+	lyt.hGroup.tabs = tabStrip{id, math.Max(finite(share), 0), true}
+	return lyt
+}
+
+func (lyt *LayoutManager) layoutTabs(g *Group) {
+	horizontal := g.LayoutType == LinearHorizontal
+	if !g.tabs.valid || !g.hasSize || !horizontal && g.LayoutType != LinearVertical || len(g.children) == 0 {
+		return
+	}
+	var (
+		cw, ch = g.Content()
+		avail = ch
+		shares float32
+	)
+	if horizontal {
+		avail = cw
+	}
+	avail -= g.Spacing * float32(len(g.children)-1)
+	for _, ii := range g.children {
+		c := &lyt.uiElements[ii]
+		avail -= c.lead
+		switch {
+		case c.group && horizontal:
+			avail -= c.W + c.Left + c.Right
+		case c.group:
+			avail -= c.H + c.Top + c.Bottom
+		case horizontal:
+			avail -= c.Left + c.Right
+		default:
+			avail -= c.Top + c.Bottom
+		}
+		if !c.group {
+			shares += g.tabs.shareOf(c.id)
+		}
+	}
+	if shares <= 0 {
+		return
+	}
+	unit := math.Max(avail, 0) / shares
+
+	lyt.arrange(g, func(g *Group, children []*Element) {
+		for _, c := range children {
+			if c.group {
+				continue
+			}
+			if horizontal {
+				c.W = unit * g.tabs.shareOf(c.id)
+			} else {
+				c.H = unit * g.tabs.shareOf(c.id)
+			}
+		}
+		restack(g, children)
+	})
+	if cw, ch = g.Content(); horizontal {
+		g.Size.W = cw
+	} else {
+		g.Size.H = ch
+	}
+}
+
+func (t tabStrip) shareOf(id ID) float32 {
+	if id == t.selected {
+		return t.share
+	}
+	return 1
+}
@@ -0,0 +1,49 @@
+package gui
+
+import (
+	"testing"
+)
+
+func TestSelectedTab(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	// 300px strip, tab 3 selected with twice the share
+	frame := func(spacing float32) {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		lyt.SetSize(300, 30).SetSpacing(spacing).SetSelectedTab(3, 2)
+		for id := ID(2); id <= 4; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(200, 30)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame(0)
+	frame(0)
+
+	a, _ := lyt.Element(2)
+	b, _ := lyt.Element(3)
+	c, _ := lyt.Element(4)
+	if b.W <= a.W || b.W <= c.W {
+		t.Error("selected tab should be wider:", a.W, b.W, c.W)
+	}
+	if a.W != 75 || b.W != 150 || c.W != 75 {
+		t.Error("tab widths:", a.W, b.W, c.W)
+	}
+	if a.X != 0 || b.X != 75 || c.X+c.W != 300 {
+		t.Error("tabs should fill the strip:", a.X, b.X, c.X)
+	}
+	if g, _ := lyt.Element(1); g.W != 300 {
+		t.Error("strip should keep its width:", g.W)
+	}
+
+	// 负的 spacing 让标签互相重叠, 仍然填满
+	frame(-10)
+	a, _ = lyt.Element(2)
+	c, _ = lyt.Element(4)
+	if a.W != 80 || c.X+c.W != 300 {
+		t.Error("overlapping tabs:", a.W, c.X, c.W)
+	}
+}
@@ -0,0 +1,67 @@
+package gui
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// 布局的层级结构, Bound 为绝对坐标
+type LayoutNode struct {
+	ID ID
+	Bound
+	// 只有 Group 有 LayoutType
+	Group bool
+	Type LayoutType
+	Children []*LayoutNode
+}
+
+// 返回默认的根布局, 子节点按声明的顺序排列
+func (lyt *LayoutManager) Tree() *LayoutNode {
+	if len(lyt.uiElements) == 0 {
+		return nil
+	}
+	var (
+		nodes = make([]LayoutNode, len(lyt.uiElements))
+		index = make(map[ID]*LayoutNode, len(lyt.uiElements))
+	)
+	for i := range lyt.uiElements {
+		elem := &lyt.uiElements[i]
+		nodes[i] = LayoutNode{ID: elem.id, Bound: lyt.absBound(elem), Group: elem.group, Type: elem.layout}
+		if elem.group {
+			if _, dup := index[elem.id]; !dup {
+				index[elem.id] = &nodes[i]
+			}
+		}
+	}
+	for i := 1; i < len(lyt.uiElements); i++ {
+		if p, ok := index[lyt.uiElements[i].parent]; ok && p != &nodes[i] {
+			p.Children = append(p.Children, &nodes[i])
+		}
+	}
+	return &nodes[0]
+}
+
+// 以文本的形式输出布局树, 每行: id type x y w h, 子节点缩进两个空格并按 id 排序,
+// 输出是确定的, 可以用来做 golden test 或者比较两次运行的结果
+func (lyt *LayoutManager) DumpTree(w io.Writer) {
+	if root := lyt.Tree(); root != nil {
+		dumpNode(w, root, 0)
+	}
+}
+
+func dumpNode(w io.Writer, n *LayoutNode, depth int) {
+	kind := "element"
+	if n.Group {
+		kind = n.Type.String()
+	}
+	fmt.Fprintf(w, "%*s%d %s %g %g %g %g\n", depth*2, "", n.ID, kind, n.X, n.Y, n.W, n.H)
+
+	children := append([]*LayoutNode(nil), n.Children...)
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].ID < children[j].ID
+	})
+	for _, c := range children {
+		dumpNode(w, c, depth+1)
+	}
+}
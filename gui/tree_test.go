@@ -0,0 +1,75 @@
+package gui
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 3))
+		for id := ID(4); id <= 5; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(10, 10)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	root := lyt.Tree()
+	if root == nil || len(root.Children) != 1 {
+		t.Fatal("root should have one child")
+	}
+	vertical := root.Children[0]
+	if vertical.ID != 1 || !vertical.Group || vertical.Type != LinearVertical || len(vertical.Children) != 2 {
+		t.Fatal("vertical group node:", vertical)
+	}
+	horizontal := vertical.Children[1]
+	if horizontal.ID != 3 || horizontal.Type != LinearHorizontal || len(horizontal.Children) != 2 {
+		t.Fatal("horizontal group node:", horizontal)
+	}
+	// nested group starts below the first child
+	if b := horizontal.Children[1].Bound; b != (Bound{14, 24, 10, 10}) {
+		t.Error("absolute bound of nested leaf:", b)
+	}
+}
+
+func TestDumpTree(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		// declared out of id order
+		for _, id := range []ID{3, 2} {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(10, 5)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	buf := &bytes.Buffer{}
+	lyt.DumpTree(buf)
+	expect := "0 LinearOverLay 0 0 0 0\n" +
+		"  1 LinearHorizontal 0 0 20 5\n" +
+		"    2 element 10 0 10 5\n" +
+		"    3 element 0 0 10 5\n"
+	if buf.String() != expect {
+		t.Errorf("dump tree:\n%s\nexpected:\n%s", buf.String(), expect)
+	}
+}
@@ -0,0 +1,227 @@
+package gui
+
+import (
+	"korok.io/korok/engi/math"
+)
+
+// 位于第 index 个子元素之前的空白
+type spacer struct {
+	index  int
+	weight float32
+}
+
+// 在当前位置插入一个没有内容的空白, 和设置了 Property.Weight 的元素一起
+// 按权重分配线性布局主轴方向的剩余空间. Group 需要设置主轴方向的大小.
+// 空白两侧不会增加 spacing, 比如两个相同权重的空白可以让中间的元素居中
+func (lyt *LayoutManager) Spacer(weight float32) *LayoutManager {
+	g := lyt.hGroup
+	g.spacers = append(g.spacers, spacer{len(g.children), weight})
+	return lyt
+}
+
+// 分配剩余空间, 然后沿主轴重新摆放子元素
+func (lyt *LayoutManager) distribute(g *Group) {
+	horizontal := g.LayoutType == LinearHorizontal
+	if !horizontal && g.LayoutType != LinearVertical || !g.hasSize {
+		return
+	}
+	var (
+		cw, ch = g.Content()
+		avail, used = ch, g.Size.H
+		total float32
+	)
+	if horizontal {
+		avail, used = cw, g.Size.W
+	}
+	for _, s := range g.spacers {
+		total += s.weight
+	}
+	for _, ii := range g.children {
+		if c := &lyt.uiElements[ii]; !c.group {
+			total += c.weight
+		}
+	}
+	left := avail - used
+	if total <= 0 || left <= 0 {
+		return
+	}
+
+	lyt.arrange(g, func(g *Group, children []*Element) {
+		var (
+			pos float32
+			next int
+		)
+		for i, c := range children {
+			for ; next < len(g.spacers) && g.spacers[next].index <= i; next++ {
+				pos += left * g.spacers[next].weight / total
+			}
+			pos += c.lead
+			if !c.group && c.weight > 0 {
+				if horizontal {
+					c.W += left * c.weight / total
+				} else {
+					c.H += left * c.weight / total
+				}
+			}
+			dx, dy := g.Extent(c)
+			ox, oy := g.slotOffset(c)
+			if horizontal {
+				c.X = g.Padding.Left + pos + c.Left + ox
+				pos += dx + g.Spacing
+			} else {
+				c.Y = g.Padding.Top + pos + c.Top + oy
+				pos += dy + g.Spacing
+			}
+		}
+	})
+	if horizontal {
+		g.Size.W = avail
+	} else {
+		g.Size.H = avail
+	}
+}
+
+// 当前线性布局的子元素超出 Group(主轴方向)时收缩子元素, 而不是超出 Group.
+// 超出的部分按 Property.ShrinkWeight 分摊给子元素(默认都是 1, 子 Group 不参与),
+// 收缩到 MinWidth/MinHeight 的元素不再参与分摊, 剩下的部分由其它元素继续分摊.
+// 所有元素都到了最小大小仍然放不下时, 内容超出 Group(见 Overflowed).
+// 默认关闭, 否则滚动容器的内容也会被压缩. Group 需要设置主轴方向的大小
+func (lyt *LayoutManager) SetShrinkToFit(shrink bool) *LayoutManager {
+	lyt.hGroup.shrink = shrink
+	return lyt
+}
+
+func (lyt *LayoutManager) shrink(g *Group) {
+	horizontal := g.LayoutType == LinearHorizontal
+	if !g.shrink || !horizontal && g.LayoutType != LinearVertical || !g.hasSize {
+		return
+	}
+	cw, ch := g.Content()
+	over := g.Size.H - ch
+	if horizontal {
+		over = g.Size.W - cw
+	}
+	if over <= 0 {
+		return
+	}
+	var shrinking []*Element
+	for _, ii := range g.children {
+		if c := &lyt.uiElements[ii]; !c.group && c.shrinkWeight() > 0 {
+			shrinking = append(shrinking, c)
+		}
+	}
+	// 每一轮至少有一个元素到达最小大小, 或者分摊完
+	var shrunk float32
+	for over > 0 && len(shrinking) > 0 {
+		var total float32
+		for _, c := range shrinking {
+			total += c.shrinkWeight()
+		}
+		next, cut := shrinking[:0], over
+		for _, c := range shrinking {
+			size, min := &c.H, c.minH
+			if horizontal {
+				size, min = &c.W, c.minW
+			}
+			d := cut * c.shrinkWeight() / total
+			if *size-d <= min {
+				d = math.Max(*size-min, 0)
+			} else {
+				next = append(next, c)
+			}
+			*size -= d
+			over, shrunk = over-d, shrunk+d
+		}
+		if len(next) == len(shrinking) {
+			break
+		}
+		shrinking = next
+	}
+	if shrunk == 0 {
+		return
+	}
+	lyt.arrange(g, restack)
+	if horizontal {
+		g.Size.W -= shrunk
+	} else {
+		g.Size.H -= shrunk
+	}
+}
+
+// 收缩的权重, 没有设置时为 1, 0 表示不收缩
+func (elem *Element) shrinkWeight() float32 {
+	if elem.hasShrink {
+		return elem.shrink
+	}
+	return 1
+}
+
+// 线性布局的子元素填不满 Group(主轴方向)时, 剩余空白的位置
+// UnderflowStart:        空白在最后(默认)
+// UnderflowEnd:          空白在最前
+// UnderflowCenter:       空白平分在两侧
+// UnderflowSpaceBetween: 空白平分在子元素之间, 只有一个子元素时同 UnderflowStart
+// UnderflowPushLast:     空白在最后一个子元素之前, 最后一个子元素贴着 Group 的末端(比如页脚)
+type Underflow uint8
+
+const (
+	UnderflowStart Underflow = iota
+	UnderflowEnd
+	UnderflowCenter
+	UnderflowSpaceBetween
+	UnderflowPushLast
+)
+
+// 设置当前 Group 的 Underflow, Group 需要设置主轴方向的大小.
+// 和 Gravity 不同, 这里移动的是整个子元素块
+func (lyt *LayoutManager) SetUnderflow(mode Underflow) *LayoutManager {
+	lyt.hGroup.underflow = mode
+	return lyt
+}
+
+// 同 SetUnderflow(UnderflowPushLast), false 时恢复 UnderflowStart
+func (lyt *LayoutManager) SetPushLastToEnd(push bool) *LayoutManager {
+	if push {
+		return lyt.SetUnderflow(UnderflowPushLast)
+	}
+	return lyt.SetUnderflow(UnderflowStart)
+}
+
+func (lyt *LayoutManager) applyUnderflow(g *Group) {
+	horizontal := g.LayoutType == LinearHorizontal
+	if g.underflow == UnderflowStart || !g.hasSize || !horizontal && g.LayoutType != LinearVertical {
+		return
+	}
+	cw, ch := g.Content()
+	left := ch - g.Size.H
+	if horizontal {
+		left = cw - g.Size.W
+	}
+	if left <= 0 || len(g.children) == 0 {
+		return
+	}
+	lyt.arrange(g, func(g *Group, children []*Element) {
+		for i, c := range children {
+			var d float32
+			switch g.underflow {
+			case UnderflowEnd:
+				d = left
+			case UnderflowCenter:
+				d = left / 2
+			case UnderflowSpaceBetween:
+				if n := len(children); n > 1 {
+					d = left * float32(i) / float32(n-1)
+				}
+			case UnderflowPushLast:
+				if i == len(children)-1 {
+					d = left
+				}
+			}
+			if horizontal {
+				c.X += d
+			} else {
+				c.Y += d
+			}
+		}
+	})
+}
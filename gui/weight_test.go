@@ -0,0 +1,199 @@
+package gui
+
+import (
+	"testing"
+)
+
+func TestSpacer(t *testing.T) {
+	lyt := New()
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		lyt.SetSize(200, 20)
+		lyt.Spacer(1)
+		elem, _ := lyt.BeginElement(2)
+		elem.Size(40, 20)
+		lyt.EndElement(elem)
+		lyt.Spacer(1)
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	if elem, _ := lyt.Element(2); elem.X != 80 {
+		t.Error("item should be centered between spacers:", elem.Bound)
+	}
+}
+
+func TestWeight(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	p := NewProperty()
+	p.Weight = 1
+
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		lyt.SetSize(200, 20)
+		for id := ID(2); id <= 3; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(20, 20)
+			lyt.EndElement(elem)
+		}
+		lyt.Spacer(2)
+		elem, _ := lyt.BeginElement(4)
+		elem.Size(20, 20)
+		lyt.EndElement(elem)
+		lyt.EndLayout()
+	}
+	frame()
+	lyt.SetProperty(3, p)
+	frame()
+
+	// 140 left: 1/3 to element 3, 2/3 to the spacer
+	if elem, _ := lyt.Element(3); elem.X != 20 || elem.W < 66.66 || elem.W > 66.67 {
+		t.Error("weighted element:", elem.Bound)
+	}
+	if elem, _ := lyt.Element(4); elem.X != 180 {
+		t.Error("element after spacer:", elem.Bound)
+	}
+}
+
+func TestUnderflow(t *testing.T) {
+	cases := []struct {
+		mode Underflow
+		y    [2]float32
+	}{
+		{UnderflowStart, [2]float32{0, 20}},
+		{UnderflowEnd, [2]float32{60, 80}},
+		{UnderflowCenter, [2]float32{30, 50}},
+		{UnderflowSpaceBetween, [2]float32{0, 80}},
+	}
+	for _, c := range cases {
+		lyt := New()
+		lyt.spacing = 0
+
+		frame := func() {
+			lyt.Move(0, 0)
+			lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+			lyt.SetSize(50, 100).SetUnderflow(c.mode)
+			for id := ID(2); id <= 3; id++ {
+				elem, _ := lyt.BeginElement(id)
+				elem.Size(50, 20)
+				lyt.EndElement(elem)
+			}
+			lyt.EndLayout()
+		}
+		frame()
+		frame()
+
+		for i, y := range c.y {
+			if elem, _ := lyt.Element(ID(2 + i)); elem.Y != y {
+				t.Error("mode", c.mode, "child", i, "placed at:", elem.Y, "expected:", y)
+			}
+		}
+	}
+}
+
+func TestPushLastToEnd(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	// 两个列表项和一个页脚
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearVertical, layoutOf(lyt, 1))
+		lyt.SetSize(100, 200).SetPushLastToEnd(true)
+		for id := ID(2); id <= 4; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(100, 30)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	frame()
+	frame()
+
+	for id, y := range map[ID]float32{2: 0, 3: 30, 4: 170} {
+		if e, _ := lyt.Element(id); e.Y != y {
+			t.Error("element", id, "placed at:", e.Y, "expected:", y)
+		}
+	}
+}
+
+func TestShrinkWeight(t *testing.T) {
+	lyt := New()
+	lyt.spacing = 0
+
+	// 100px row with three 60px children
+	fit := false
+	frame := func() {
+		lyt.Move(0, 0)
+		lyt.PushLayout(LinearHorizontal, layoutOf(lyt, 1))
+		lyt.SetSize(100, 20).SetShrinkToFit(fit)
+		for id := ID(2); id <= 4; id++ {
+			elem, _ := lyt.BeginElement(id)
+			elem.Size(60, 20)
+			lyt.EndElement(elem)
+		}
+		lyt.EndLayout()
+	}
+	widths := func() (w [3]float32) {
+		for i := range w {
+			e, _ := lyt.Element(ID(2 + i))
+			w[i] = e.W
+		}
+		return
+	}
+	frame()
+	frame()
+	if w := widths(); w != [3]float32{60, 60, 60} {
+		t.Error("row should not shrink unless SetShrinkToFit:", w)
+	}
+	fit = true
+	frame()
+	if w := widths(); w[0] != w[1] || w[1] != w[2] || !near(w[0]+w[1]+w[2], 100) {
+		t.Error("over-full row should shrink evenly:", w)
+	}
+
+	// child 3 stops at its minimum, the rest is shared by the others
+	p := NewProperty()
+	p.MinWidth = 40
+	lyt.SetProperty(3, p)
+	frame()
+	if w := widths(); w != [3]float32{30, 40, 30} {
+		t.Error("children should shrink down to their mins:", w)
+	}
+	if e, _ := lyt.Element(4); e.X != 70 {
+		t.Error("children should be restacked:", e.X)
+	}
+	if lyt.Overflowed(1) {
+		t.Error("shrunk row should fit")
+	}
+
+	// 都到了最小大小仍然放不下
+	p.MinWidth = 50
+	for id := ID(2); id <= 4; id++ {
+		lyt.SetProperty(id, p)
+	}
+	frame()
+	if w := widths(); w != [3]float32{50, 50, 50} {
+		t.Error("children should stop at their mins:", w)
+	}
+	if !lyt.Overflowed(1) {
+		t.Error("row should overflow when the mins don't fit")
+	}
+
+	// ShrinkWeight 为 0 的元素保持原来的大小
+	fixed := NewProperty()
+	fixed.ShrinkWeight = 0
+	lyt.SetProperty(2, fixed)
+	lyt.SetProperty(3, NewProperty())
+	lyt.SetProperty(4, NewProperty())
+	frame()
+	if w := widths(); w != [3]float32{60, 20, 20} {
+		t.Error("zero shrink weight should keep its size:", w)
+	}
+}